@@ -1,29 +1,40 @@
 package cards
 
-import (
-	"errors"
-)
-
 // ActionShowCard defines an AdaptiveCard which is shown to the user when the button or link is clicked.
 type ActionShowCard struct {
 	Type string     `json:"type"` // required
 	Card NestedCard `json:"card,omitempty"`
 	// inherited
 	Title    string            `json:"title,omitempty"`
+	ID       string            `json:"id,omitempty"`
 	IconURL  string            `json:"iconUrl,omitempty"`
-	Style    string            `json:"style,omitempty"`
-	Fallback []Node            `json:"fallback,omitempty"`
+	Style    ActionStyle       `json:"style,omitempty"`
+	Fallback *Fallback         `json:"fallback,omitempty"`
 	Requires map[string]string `json:"requires,omitempty"`
 }
 
-func (n *ActionShowCard) prepare() error {
+// NodeType returns ActionShowCard element type
+func (n *ActionShowCard) NodeType() string {
+	return ActionShowCardType
+}
+
+// Prepare sets ActionShowCard type, validates its style and prepares its card
+func (n *ActionShowCard) Prepare() error {
 	n.Type = ActionShowCardType
+	if err := check(n); err != nil {
+		return err
+	}
 	if err := n.Card.prepare(); err != nil {
 		return err
 	}
 	return nil
 }
 
+func (n *ActionShowCard) check(r *reporter) {
+	r.enum("style", n.Style)
+	n.Card.check(r.at("card"))
+}
+
 // ActionSubmit gathers input fields, merges with optional data field,
 // and sends an event to the client.
 // It is up to the client to determine how this data is processed.
@@ -34,15 +45,26 @@ type ActionSubmit struct {
 	AssociatedInputs string                 `json:"associatedInputs,omitempty"`
 	// inherited
 	Title    string            `json:"title,omitempty"`
+	ID       string            `json:"id,omitempty"`
 	IconURL  string            `json:"iconUrl,omitempty"`
-	Style    string            `json:"style,omitempty"`
-	Fallback []Node            `json:"fallback,omitempty"`
+	Style    ActionStyle       `json:"style,omitempty"`
+	Fallback *Fallback         `json:"fallback,omitempty"`
 	Requires map[string]string `json:"requires,omitempty"`
 }
 
-func (n *ActionSubmit) prepare() error {
+// NodeType returns ActionSubmit element type
+func (n *ActionSubmit) NodeType() string {
+	return ActionSubmitType
+}
+
+// Prepare sets ActionSubmit type and validates its style
+func (n *ActionSubmit) Prepare() error {
 	n.Type = ActionSubmitType
-	return nil
+	return check(n)
+}
+
+func (n *ActionSubmit) check(r *reporter) {
+	r.enum("style", n.Style)
 }
 
 // ActionOpenURL when invoked, show the given url
@@ -52,15 +74,26 @@ type ActionOpenURL struct {
 	URL  string `json:"url"`  // required
 	// inherited
 	Title    string            `json:"title,omitempty"`
+	ID       string            `json:"id,omitempty"`
 	IconURL  string            `json:"iconUrl,omitempty"`
-	Style    string            `json:"style,omitempty"`
-	Fallback []Node            `json:"fallback,omitempty"`
+	Style    ActionStyle       `json:"style,omitempty"`
+	Fallback *Fallback         `json:"fallback,omitempty"`
 	Requires map[string]string `json:"requires,omitempty"`
 }
 
-func (n *ActionOpenURL) prepare() error {
+// NodeType returns ActionOpenURL element type
+func (n *ActionOpenURL) NodeType() string {
+	return ActionOpenURLType
+}
+
+// Prepare sets ActionOpenURL type and validates its style
+func (n *ActionOpenURL) Prepare() error {
 	n.Type = ActionOpenURLType
-	return nil
+	return check(n)
+}
+
+func (n *ActionOpenURL) check(r *reporter) {
+	r.enum("style", n.Style)
 }
 
 // ActionToggleVisibility toggles the visibility of associated card elements.
@@ -69,20 +102,29 @@ type ActionToggleVisibility struct {
 	TargetElements []TargetElement `json:"targetElements,omitempty"`
 	// inherited
 	Title    string            `json:"title,omitempty"`
+	ID       string            `json:"id,omitempty"`
 	IconURL  string            `json:"iconUrl,omitempty"`
-	Style    string            `json:"style,omitempty"`
-	Fallback []Node            `json:"fallback,omitempty"`
+	Style    ActionStyle       `json:"style,omitempty"`
+	Fallback *Fallback         `json:"fallback,omitempty"`
 	Requires map[string]string `json:"requires,omitempty"`
 }
 
-func (n *ActionToggleVisibility) prepare() error {
+// NodeType returns ActionToggleVisibility element type
+func (n *ActionToggleVisibility) NodeType() string {
+	return ActionToggleVisibilityType
+}
+
+// Prepare sets ActionToggleVisibility type and validates its style and target elements
+func (n *ActionToggleVisibility) Prepare() error {
 	n.Type = ActionToggleVisibilityType
-	for _, e := range n.TargetElements {
-		if err := e.prepare(); err != nil {
-			return err
-		}
+	return check(n)
+}
+
+func (n *ActionToggleVisibility) check(r *reporter) {
+	r.enum("style", n.Style)
+	for i := range n.TargetElements {
+		n.TargetElements[i].check(r.at("targetElements/%d", i))
 	}
-	return nil
 }
 
 // TargetElement represents an entry for Action.ToggleVisibility's targetElements property
@@ -91,20 +133,20 @@ type TargetElement struct {
 	IsVisible *bool  `json:"isVisible,omitempty"`
 }
 
-func (t *TargetElement) prepare() error {
+func (t *TargetElement) check(r *reporter) {
 	if t.ElementID == "" {
-		return errors.New("TargetElement element id is required")
+		r.required("TargetElement element id is required")
 	}
-	return nil
 }
 
 // ActionInheritedFields holds common inherited fields for actions.
 // Not really used for the brewety of API.
 type ActionInheritedFields struct {
 	Title    string            `json:"title,omitempty"`
+	ID       string            `json:"id,omitempty"`
 	IconURL  string            `json:"iconUrl,omitempty"`
-	Style    string            `json:"style,omitempty"`
-	Fallback []Node            `json:"fallback,omitempty"`
+	Style    ActionStyle       `json:"style,omitempty"`
+	Fallback *Fallback         `json:"fallback,omitempty"`
 	Requires map[string]string `json:"requires,omitempty"`
 }
 
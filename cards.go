@@ -2,7 +2,6 @@ package cards
 
 import (
 	"encoding/json"
-	"errors"
 )
 
 const (
@@ -17,6 +16,10 @@ const (
 	// Version13 is cards verstion 1.3.
 	// Warning maybe not supported by bot framework!
 	Version13 = "1.3"
+	// Version14 is cards version 1.4
+	Version14 = "1.4"
+	// Version15 is cards version 1.5
+	Version15 = "1.5"
 
 	// Types
 
@@ -66,25 +69,32 @@ const (
 	InputToggleType = "Input.Toggle"
 )
 
-// Node is card element
+// Node is card element.
+// Custom element types implement it and are made known to the package with RegisterType.
 type Node interface {
-	prepare() error
+	// NodeType returns element type which is used as "type" JSON field, e.g. "TextBlock"
+	NodeType() string
+	// Prepare fills "type" with NodeType, returns the first problem found by the element's own checks
+	// (required fields, enum values, ranges) and then calls Prepare of its child elements.
+	Prepare() error
 }
 
 // Card is basic adaptive cards type.
 type Card struct {
-	Type                     string           `json:"type"`    // required
-	Version                  string           `json:"version"` // required
-	Schema                   string           `json:"$schema,omitempty"`
-	Body                     []Node           `json:"body,omitempty"`
-	Actions                  []Node           `json:"actions,omitempty"`
-	SelectAction             Node             `json:"selectAction,omitempty"`
-	FallbackText             string           `json:"fallbackText,omitempty"`
-	BackgroundImage          *BackgroundImage `json:"backgroundImage,omitempty"`
-	MinHeight                string           `json:"minHeight,omitempty"`
-	Speak                    string           `json:"speak,omitempty"`
-	Lang                     string           `json:"lang,omitempty"`
-	VerticalContentAlignment string           `json:"verticalContentAlignment,omitempty"`
+	Type                     string            `json:"type"`    // required
+	Version                  string            `json:"version"` // required
+	Schema                   string            `json:"$schema,omitempty"`
+	Body                     []Node            `json:"body,omitempty"`
+	Actions                  []Node            `json:"actions,omitempty"`
+	SelectAction             Node              `json:"selectAction,omitempty"`
+	FallbackText             string            `json:"fallbackText,omitempty"`
+	BackgroundImage          *BackgroundImage  `json:"backgroundImage,omitempty"`
+	MinHeight                string            `json:"minHeight,omitempty"`
+	Speak                    string            `json:"speak,omitempty"`
+	Lang                     string            `json:"lang,omitempty"`
+	VerticalContentAlignment VerticalAlignment `json:"verticalContentAlignment,omitempty"`
+
+	Summarizer *Summarizer `json:"-"` // if set, empty FallbackText and Speak are generated on serialization
 }
 
 // New returns a card with provided body and default schema
@@ -124,25 +134,31 @@ func (c *Card) WithMinHeight(h string) *Card {
 // Prepare validates card (required fields etc) and sets relevant types
 func (c *Card) Prepare() error {
 	c.Type = AdaptiveCardType
-	if c.Version == "" {
-		return errors.New("card version is required")
+	if err := check(c); err != nil {
+		return err
 	}
 	for _, node := range c.Body {
-		if err := node.prepare(); err != nil {
+		if err := prepareNode(node); err != nil {
 			return err
 		}
 	}
 	for _, node := range c.Actions {
-		if err := node.prepare(); err != nil {
+		if err := prepareNode(node); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+func (c *Card) check(r *reporter) {
+	if c.Version == "" {
+		r.required("card version is required")
+	}
+	r.pixels("minHeight", c.MinHeight)
+	r.enum("verticalContentAlignment", c.VerticalContentAlignment)
 	if c.BackgroundImage != nil {
-		if err := c.BackgroundImage.prepare(); err != nil {
-			return err
-		}
+		c.BackgroundImage.check(r.at("backgroundImage"))
 	}
-	return nil
 }
 
 // Bytes returns adaptive card JSON as bytes
@@ -150,7 +166,7 @@ func (c *Card) Bytes() ([]byte, error) {
 	if err := c.Prepare(); err != nil {
 		return []byte{}, err
 	}
-	return json.Marshal(c)
+	return json.Marshal(c.summarized())
 }
 
 // String returns adaptive card JSON as string
@@ -167,7 +183,18 @@ func (c *Card) BytesIndent(prefix string, indent string) ([]byte, error) {
 	if err := c.Prepare(); err != nil {
 		return []byte{}, err
 	}
-	return json.MarshalIndent(c, prefix, indent)
+	return json.MarshalIndent(c.summarized(), prefix, indent)
+}
+
+// summarized returns the card to encode: a shallow copy with generated summary
+// if the card has Summarizer, so the card itself keeps empty fields
+func (c *Card) summarized() *Card {
+	if c.Summarizer == nil {
+		return c
+	}
+	res := *c
+	c.Summarizer.Apply(&res)
+	return &res
 }
 
 // StringIndent returns adaptive card JSON as string with indentation
@@ -181,51 +208,59 @@ func (c *Card) StringIndent(prefix string, indent string) (string, error) {
 
 // NestedCard is similar to adaptive card but doesn't require version and schema
 type NestedCard struct {
-	Type                     string           `json:"type"` // required
-	Version                  string           `json:"version,omitempty"`
-	Schema                   string           `json:"$schema,omitempty"`
-	Body                     []Node           `json:"body,omitempty"`
-	Actions                  []Node           `json:"actions,omitempty"`
-	SelectAction             Node             `json:"selectAction,omitempty"`
-	FallbackText             string           `json:"fallbackText,omitempty"`
-	BackgroundImage          *BackgroundImage `json:"backgroundImage,omitempty"`
-	MinHeight                string           `json:"minHeight,omitempty"`
-	Speak                    string           `json:"speak,omitempty"`
-	Lang                     string           `json:"lang,omitempty"`
-	VerticalContentAlignment string           `json:"verticalContentAlignment,omitempty"`
+	Type                     string            `json:"type"` // required
+	Version                  string            `json:"version,omitempty"`
+	Schema                   string            `json:"$schema,omitempty"`
+	Body                     []Node            `json:"body,omitempty"`
+	Actions                  []Node            `json:"actions,omitempty"`
+	SelectAction             Node              `json:"selectAction,omitempty"`
+	FallbackText             string            `json:"fallbackText,omitempty"`
+	BackgroundImage          *BackgroundImage  `json:"backgroundImage,omitempty"`
+	MinHeight                string            `json:"minHeight,omitempty"`
+	Speak                    string            `json:"speak,omitempty"`
+	Lang                     string            `json:"lang,omitempty"`
+	VerticalContentAlignment VerticalAlignment `json:"verticalContentAlignment,omitempty"`
 }
 
 func (n *NestedCard) prepare() error {
 	n.Type = AdaptiveCardType
+	if err := check(n); err != nil {
+		return err
+	}
 	for _, node := range n.Body {
-		if err := node.prepare(); err != nil {
+		if err := prepareNode(node); err != nil {
 			return err
 		}
 	}
 	for _, node := range n.Actions {
-		if err := node.prepare(); err != nil {
+		if err := prepareNode(node); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+func (n *NestedCard) check(r *reporter) {
+	r.pixels("minHeight", n.MinHeight)
+	r.enum("verticalContentAlignment", n.VerticalContentAlignment)
 	if n.BackgroundImage != nil {
-		if err := n.BackgroundImage.prepare(); err != nil {
-			return err
-		}
+		n.BackgroundImage.check(r.at("backgroundImage"))
 	}
-	return nil
 }
 
 // BackgroundImage specifies a background image. Acceptable formats are PNG, JPEG, and GIF.
 type BackgroundImage struct {
-	URL                 string `json:"url"` // required
-	FillMode            string `json:"fillMode,omitempty"`
-	HorizontalAlignment string `json:"horizontalAlignment,omitempty"`
-	VerticalAlignment   string `json:"verticalAlignment,omitempty"`
+	URL                 string              `json:"url"` // required
+	FillMode            FillMode            `json:"fillMode,omitempty"`
+	HorizontalAlignment HorizontalAlignment `json:"horizontalAlignment,omitempty"`
+	VerticalAlignment   VerticalAlignment   `json:"verticalAlignment,omitempty"`
 }
 
-func (b *BackgroundImage) prepare() error {
+func (b *BackgroundImage) check(r *reporter) {
 	if b.URL == "" {
-		return errors.New("BackgroundImage must have url")
+		r.required("BackgroundImage must have url")
 	}
-	return nil
+	r.enum("fillMode", b.FillMode)
+	r.enum("horizontalAlignment", b.HorizontalAlignment)
+	r.enum("verticalAlignment", b.VerticalAlignment)
 }
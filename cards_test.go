@@ -72,8 +72,8 @@ func TestInputsCard(t *testing.T) {
 		},
 		&InputNumber{
 			ID:    "NumVal",
-			Max:   5,
-			Min:   -5,
+			Max:   FloatPtr(5),
+			Min:   FloatPtr(-5),
 			Value: 1,
 		},
 		&TextBlock{
@@ -0,0 +1,69 @@
+package cards
+
+import (
+	"reflect"
+)
+
+// Clone returns deep copy of the card
+func (c *Card) Clone() *Card {
+	return deepCopy(reflect.ValueOf(c)).Interface().(*Card)
+}
+
+// CloneNode returns deep copy of the element
+func CloneNode(n Node) Node {
+	if n == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(n)).Interface().(Node)
+}
+
+// deepCopy copies pointers, slices, maps and interfaces recursively.
+// Unexported struct fields are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.New(v.Elem().Type())
+		res.Elem().Set(deepCopy(v.Elem()))
+		return res
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.New(v.Type()).Elem()
+		res.Set(deepCopy(v.Elem()))
+		return res
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return res
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			res.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return res
+	case reflect.Struct:
+		res := reflect.New(v.Type()).Elem()
+		res.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue // unexported
+			}
+			res.Field(i).Set(deepCopy(v.Field(i)))
+		}
+		return res
+	}
+	return v
+}
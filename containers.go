@@ -1,130 +1,187 @@
 package cards
 
-import (
-	"errors"
-)
-
 // ActionSet displays a set of actions.
 type ActionSet struct {
 	Type    string `json:"type"` // required
 	Actions []Node `json:"actions,omitempty"`
 	// inherited
-	Fallback  []Node            `json:"fallback,omitempty"`
-	Height    string            `json:"height,omitempty"`
-	Separator *bool             `json:"separator,omitempty"`
-	Spacing   string            `json:"spacing,omitempty"`
-	ID        string            `json:"id,omitempty"`
-	IsVisible *bool             `json:"isVisible,omitempty"`
-	Requires  map[string]string `json:"requires,omitempty"`
+	Fallback  *Fallback          `json:"fallback,omitempty"`
+	Height    BlockElementHeight `json:"height,omitempty"`
+	Separator *bool              `json:"separator,omitempty"`
+	Spacing   Spacing            `json:"spacing,omitempty"`
+	ID        string             `json:"id,omitempty"`
+	IsVisible *bool              `json:"isVisible,omitempty"`
+	Requires  map[string]string  `json:"requires,omitempty"`
 }
 
-func (n *ActionSet) prepare() error {
+// NodeType returns ActionSet element type
+func (n *ActionSet) NodeType() string {
+	return ActionSetType
+}
+
+// Prepare sets ActionSet type, validates its fields and prepares its actions
+func (n *ActionSet) Prepare() error {
 	n.Type = ActionSetType
-	if len(n.Actions) < 1 {
-		return errors.New("ActionSet must have elements")
+	if err := check(n); err != nil {
+		return err
 	}
 	for _, node := range n.Actions {
-		if err := node.prepare(); err != nil {
+		if err := prepareNode(node); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (n *ActionSet) check(r *reporter) {
+	if len(n.Actions) < 1 {
+		r.required("ActionSet must have elements")
+	}
+	r.layout(n.Height, n.Spacing)
+}
+
 // Container groups items together.
 type Container struct {
-	Type            string           `json:"type"`  // required
-	Items           []Node           `json:"items"` // required
-	SelectAction    Node             `json:"selectAction,omitempty"`
-	Style           string           `json:"style,omitempty"`
-	Bleed           *bool            `json:"bleed,omitempty"`
-	BackgroundImage *BackgroundImage `json:"backgroundImage,omitempty"`
-	MinHeight       string           `json:"minHeight,omitempty"`
+	Type                     string            `json:"type"`  // required
+	Items                    []Node            `json:"items"` // required
+	SelectAction             Node              `json:"selectAction,omitempty"`
+	Style                    ContainerStyle    `json:"style,omitempty"`
+	Bleed                    *bool             `json:"bleed,omitempty"`
+	BackgroundImage          *BackgroundImage  `json:"backgroundImage,omitempty"`
+	MinHeight                string            `json:"minHeight,omitempty"`
+	VerticalContentAlignment VerticalAlignment `json:"verticalContentAlignment,omitempty"`
 	// inherited
-	Fallback  []Node            `json:"fallback,omitempty"`
-	Height    string            `json:"height,omitempty"`
-	Separator *bool             `json:"separator,omitempty"`
-	Spacing   string            `json:"spacing,omitempty"`
-	ID        string            `json:"id,omitempty"`
-	IsVisible *bool             `json:"isVisible,omitempty"`
-	Requires  map[string]string `json:"requires,omitempty"`
+	Fallback  *Fallback          `json:"fallback,omitempty"`
+	Height    BlockElementHeight `json:"height,omitempty"`
+	Separator *bool              `json:"separator,omitempty"`
+	Spacing   Spacing            `json:"spacing,omitempty"`
+	ID        string             `json:"id,omitempty"`
+	IsVisible *bool              `json:"isVisible,omitempty"`
+	Requires  map[string]string  `json:"requires,omitempty"`
 }
 
-func (n *Container) prepare() error {
+// NodeType returns Container element type
+func (n *Container) NodeType() string {
+	return ContainerType
+}
+
+// Prepare sets Container type, validates its fields and prepares its items
+func (n *Container) Prepare() error {
 	n.Type = ContainerType
-	if len(n.Items) < 1 {
-		return errors.New("container must have elements")
+	if err := check(n); err != nil {
+		return err
 	}
 	for _, node := range n.Items {
-		if err := node.prepare(); err != nil {
+		if err := prepareNode(node); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (n *Container) check(r *reporter) {
+	if len(n.Items) < 1 {
+		r.required("container must have elements")
+	}
+	r.enum("style", n.Style)
+	r.pixels("minHeight", n.MinHeight)
+	r.enum("verticalContentAlignment", n.VerticalContentAlignment)
+	r.layout(n.Height, n.Spacing)
+	if n.BackgroundImage != nil {
+		n.BackgroundImage.check(r.at("backgroundImage"))
+	}
+}
+
 // ColumnSet divides a region into Columns,
 // allowing elements to sit side-by-side.
 type ColumnSet struct {
-	Type                string    `json:"type"` // required
-	Columns             []*Column `json:"columns,omitempty"`
-	SelectAction        Node      `json:"selectAction,omitempty"`
-	Style               string    `json:"style,omitempty"`
-	Bleed               *bool     `json:"bleed,omitempty"`
-	MinHeight           string    `json:"minHeight,omitempty"`
-	HorizontalAlignment string    `json:"horizontalAlignment,omitempty"`
+	Type                string              `json:"type"` // required
+	Columns             []*Column           `json:"columns,omitempty"`
+	SelectAction        Node                `json:"selectAction,omitempty"`
+	Style               ContainerStyle      `json:"style,omitempty"`
+	Bleed               *bool               `json:"bleed,omitempty"`
+	MinHeight           string              `json:"minHeight,omitempty"`
+	HorizontalAlignment HorizontalAlignment `json:"horizontalAlignment,omitempty"`
 	// inherited
-	Fallback  []Node            `json:"fallback,omitempty"`
-	Height    string            `json:"height,omitempty"`
-	Spacing   string            `json:"spacing,omitempty"`
-	ID        string            `json:"id,omitempty"`
-	IsVisible *bool             `json:"isVisible,omitempty"`
-	Requires  map[string]string `json:"requires,omitempty"`
+	Fallback  *Fallback          `json:"fallback,omitempty"`
+	Height    BlockElementHeight `json:"height,omitempty"`
+	Spacing   Spacing            `json:"spacing,omitempty"`
+	ID        string             `json:"id,omitempty"`
+	IsVisible *bool              `json:"isVisible,omitempty"`
+	Requires  map[string]string  `json:"requires,omitempty"`
 }
 
-func (n *ColumnSet) prepare() error {
+// NodeType returns ColumnSet element type
+func (n *ColumnSet) NodeType() string {
+	return ColumnSetType
+}
+
+// Prepare sets ColumnSet type, validates its fields and prepares its columns
+func (n *ColumnSet) Prepare() error {
 	n.Type = ColumnSetType
+	if err := check(n); err != nil {
+		return err
+	}
 	for _, c := range n.Columns {
-		if err := c.prepare(); err != nil {
+		if err := prepareNode(c); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (n *ColumnSet) check(r *reporter) {
+	r.enum("style", n.Style)
+	r.pixels("minHeight", n.MinHeight)
+	r.enum("horizontalAlignment", n.HorizontalAlignment)
+	r.layout(n.Height, n.Spacing)
+}
+
 // Column defines a container that is part of a ColumnSet.
 type Column struct {
-	Type                     string           `json:"type"` // required - it is not stated in a.c. docs but actually has to be "Column"
-	Items                    []Node           `json:"items,omitempty"`
-	BackgroundImage          *BackgroundImage `json:"backgroundImage,omitempty"`
-	Bleed                    *bool            `json:"bleed,omitempty"`
-	Fallback                 Node             `json:"fallback,omitempty"`
-	MinHeight                string           `json:"minHeight,omitempty"`
-	Separator                *bool            `json:"separator,omitempty"`
-	Spacing                  string           `json:"spacing,omitempty"`
-	SelectAction             Node             `json:"selectAction,omitempty"`
-	Style                    string           `json:"style,omitempty"`
-	VerticalContentAlignment string           `json:"verticalContentAlignment,omitempty"`
-	Width                    string           `json:"width,omitempty"`
+	Type                     string            `json:"type"` // required - it is not stated in a.c. docs but actually has to be "Column"
+	Items                    []Node            `json:"items,omitempty"`
+	BackgroundImage          *BackgroundImage  `json:"backgroundImage,omitempty"`
+	Bleed                    *bool             `json:"bleed,omitempty"`
+	Fallback                 *Fallback         `json:"fallback,omitempty"`
+	MinHeight                string            `json:"minHeight,omitempty"`
+	Separator                *bool             `json:"separator,omitempty"`
+	Spacing                  Spacing           `json:"spacing,omitempty"`
+	SelectAction             Node              `json:"selectAction,omitempty"`
+	Style                    ContainerStyle    `json:"style,omitempty"`
+	VerticalContentAlignment VerticalAlignment `json:"verticalContentAlignment,omitempty"`
+	Width                    ColumnWidth       `json:"width,omitempty"`
 	// inherited
 	ID        string            `json:"id,omitempty"`
 	IsVisible *bool             `json:"isVisible,omitempty"`
 	Requires  map[string]string `json:"requires,omitempty"`
 }
 
-func (c *Column) prepare() error {
+// NodeType returns Column element type
+func (c *Column) NodeType() string {
+	return ColumnType
+}
+
+// Prepare sets Column type, prepares its items and validates its fields
+func (c *Column) Prepare() error {
 	c.Type = ColumnType
 	for _, node := range c.Items {
-		if err := node.prepare(); err != nil {
+		if err := prepareNode(node); err != nil {
 			return err
 		}
 	}
+	return check(c)
+}
+
+func (c *Column) check(r *reporter) {
+	r.enum("style", c.Style)
+	r.enum("width", c.Width)
+	r.pixels("minHeight", c.MinHeight)
+	r.enum("verticalContentAlignment", c.VerticalContentAlignment)
+	r.enum("spacing", c.Spacing)
 	if c.BackgroundImage != nil {
-		if err := c.BackgroundImage.prepare(); err != nil {
-			return err
-		}
+		c.BackgroundImage.check(r.at("backgroundImage"))
 	}
-	return nil
 }
 
 // FactSet element displays a series of facts (i.e. name/value pairs) in a tabular form.
@@ -132,26 +189,34 @@ type FactSet struct {
 	Type  string  `json:"type"`  // required - must be "FactSet"
 	Facts []*Fact `json:"facts"` // required
 	// inherited
-	Fallback  []Node            `json:"fallback,omitempty"`
-	Height    string            `json:"height,omitempty"`
-	Separator *bool             `json:"separator,omitempty"`
-	Spacing   string            `json:"spacing,omitempty"`
-	ID        string            `json:"id,omitempty"`
-	IsVisible *bool             `json:"isVisible,omitempty"`
-	Requires  map[string]string `json:"requires,omitempty"`
+	Fallback  *Fallback          `json:"fallback,omitempty"`
+	Height    BlockElementHeight `json:"height,omitempty"`
+	Separator *bool              `json:"separator,omitempty"`
+	Spacing   Spacing            `json:"spacing,omitempty"`
+	ID        string             `json:"id,omitempty"`
+	IsVisible *bool              `json:"isVisible,omitempty"`
+	Requires  map[string]string  `json:"requires,omitempty"`
 }
 
-func (n *FactSet) prepare() error {
+// NodeType returns FactSet element type
+func (n *FactSet) NodeType() string {
+	return FactSetType
+}
+
+// Prepare sets FactSet type and validates its fields and facts
+func (n *FactSet) Prepare() error {
 	n.Type = FactSetType
+	return check(n)
+}
+
+func (n *FactSet) check(r *reporter) {
 	if len(n.Facts) < 1 {
-		return errors.New("FactSet must have facts")
+		r.required("FactSet must have facts")
 	}
-	for _, f := range n.Facts {
-		if err := f.prepare(); err != nil {
-			return err
-		}
+	r.layout(n.Height, n.Spacing)
+	for i, f := range n.Facts {
+		f.check(r.at("facts/%d", i))
 	}
-	return nil
 }
 
 // Fact describes a Fact in a FactSet as a key/value pair.
@@ -160,40 +225,53 @@ type Fact struct {
 	Value string `json:"value"` // required
 }
 
-func (f *Fact) prepare() error {
+func (f *Fact) check(r *reporter) {
 	if f.Title == "" {
-		return errors.New("Fact must have title")
+		r.required("Fact must have title")
 	}
 	if f.Value == "" {
-		return errors.New("Fact must have value")
+		r.required("Fact must have value")
 	}
-	return nil
 }
 
 // ImageSet displays a collection of Images similar to a gallery. Acceptable formats are PNG, JPEG, and GIF.
 type ImageSet struct {
-	Type      string   `json:"type"`   // required
-	Images    []*Image `json:"images"` // required
-	ImageSize string   `json:"imageSize,omitempty"`
+	Type      string    `json:"type"`   // required
+	Images    []*Image  `json:"images"` // required
+	ImageSize ImageSize `json:"imageSize,omitempty"`
 	// inherited
-	Fallback  []Node            `json:"fallback,omitempty"`
-	Height    string            `json:"height,omitempty"`
-	Separator *bool             `json:"separator,omitempty"`
-	Spacing   string            `json:"spacing,omitempty"`
-	ID        string            `json:"id,omitempty"`
-	IsVisible *bool             `json:"isVisible,omitempty"`
-	Requires  map[string]string `json:"requires,omitempty"`
+	Fallback  *Fallback          `json:"fallback,omitempty"`
+	Height    BlockElementHeight `json:"height,omitempty"`
+	Separator *bool              `json:"separator,omitempty"`
+	Spacing   Spacing            `json:"spacing,omitempty"`
+	ID        string             `json:"id,omitempty"`
+	IsVisible *bool              `json:"isVisible,omitempty"`
+	Requires  map[string]string  `json:"requires,omitempty"`
 }
 
-func (n *ImageSet) prepare() error {
+// NodeType returns ImageSet element type
+func (n *ImageSet) NodeType() string {
+	return ImageSetType
+}
+
+// Prepare sets ImageSet type, validates its fields and prepares its images
+func (n *ImageSet) Prepare() error {
 	n.Type = ImageSetType
-	if len(n.Images) < 1 {
-		return errors.New("ImageSet must have images")
+	if err := check(n); err != nil {
+		return err
 	}
 	for _, f := range n.Images {
-		if err := f.prepare(); err != nil {
+		if err := prepareNode(f); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+func (n *ImageSet) check(r *reporter) {
+	if len(n.Images) < 1 {
+		r.required("ImageSet must have images")
+	}
+	r.enum("imageSize", n.ImageSize)
+	r.layout(n.Height, n.Spacing)
+}
@@ -0,0 +1,321 @@
+package cards
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var nodeInterfaceType = reflect.TypeOf((*Node)(nil)).Elem()
+
+// Parse reads adaptive card JSON and decodes it into the card
+func Parse(r io.Reader) (*Card, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var c Card
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// UnmarshalJSON decodes card JSON restoring concrete element types
+func (c *Card) UnmarshalJSON(data []byte) error {
+	return decodeJSON(data, c)
+}
+
+// UnmarshalJSON decodes nested card JSON restoring concrete element types
+func (n *NestedCard) UnmarshalJSON(data []byte) error {
+	return decodeJSON(data, n)
+}
+
+// UnmarshalJSON decodes column JSON restoring concrete element types
+func (c *Column) UnmarshalJSON(data []byte) error {
+	return decodeJSON(data, c)
+}
+
+// UnmarshalJSON decodes image JSON restoring concrete select action type
+func (n *Image) UnmarshalJSON(data []byte) error {
+	return decodeJSON(data, n)
+}
+
+// UnmarshalJSON decodes text run JSON restoring concrete select action type
+func (t *TextRun) UnmarshalJSON(data []byte) error {
+	return decodeJSON(data, t)
+}
+
+var (
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	fallbackType    = reflect.TypeOf(Fallback{})
+	// treeTypes are decoded from trees field by field, their UnmarshalJSON calls decodeJSON
+	treeTypes = map[reflect.Type]bool{
+		reflect.TypeOf(Card{}):       true,
+		reflect.TypeOf(NestedCard{}): true,
+		reflect.TypeOf(Column{}):     true,
+		reflect.TypeOf(Image{}):      true,
+		reflect.TypeOf(TextRun{}):    true,
+		fallbackType:                 true,
+	}
+)
+
+// decodeJSON decodes JSON into the tree of maps, slices and values and fills v from it.
+// Numbers are kept as json.Number so integers are decoded exactly.
+func decodeJSON(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var tree interface{}
+	if err := d.Decode(&tree); err != nil {
+		return err
+	}
+	return decodeTree(tree, v)
+}
+
+// decodeTree fills value pointed by v from the tree (maps, slices, strings, numbers and bools
+// as decoded from JSON) like json.Unmarshal does restoring concrete element types.
+// Templates are expanded into such trees, so they are decoded without encoding them first.
+func decodeTree(tree interface{}, v interface{}) error {
+	return decodeTreeValue(tree, reflect.ValueOf(v).Elem())
+}
+
+// decodeTreeNode decodes single element dispatching on its "type" field
+func decodeTreeNode(tree interface{}) (Node, error) {
+	if tree == nil {
+		return nil, nil
+	}
+	obj, ok := tree.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("element must be an object, got %s", jsonKind(tree))
+	}
+	typ, _ := obj["type"].(string)
+	if typ == "" {
+		return nil, fmt.Errorf("element type is required")
+	}
+	factory, ok := lookupType(typ)
+	if !ok {
+		return nil, fmt.Errorf("unknown element type %q", typ)
+	}
+	node := factory()
+	if node == nil {
+		return nil, fmt.Errorf("factory for %q returned nil", typ)
+	}
+	if err := decodeTreeValue(tree, reflect.ValueOf(node).Elem()); err != nil {
+		return nil, fmt.Errorf("%s: %w", typ, err)
+	}
+	return node, nil
+}
+
+func decodeTreeValue(tree interface{}, fv reflect.Value) error {
+	t := fv.Type()
+	switch {
+	case tree == nil:
+		return nil // like json.Unmarshal, null leaves the value as is
+	case t == nodeInterfaceType:
+		node, err := decodeTreeNode(tree)
+		if err != nil {
+			return err
+		}
+		if node != nil {
+			fv.Set(reflect.ValueOf(node))
+		}
+		return nil
+	case t == fallbackType:
+		if s, ok := tree.(string); ok {
+			if !strings.EqualFold(s, fallbackDrop) {
+				return fmt.Errorf("fallback must be an element or %q, got %q", fallbackDrop, s)
+			}
+			fv.Set(reflect.ValueOf(Fallback{Drop: true}))
+			return nil
+		}
+		node, err := decodeTreeNode(tree)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(Fallback{Element: node}))
+		return nil
+	case !treeTypes[t] && reflect.PtrTo(t).Implements(unmarshalerType):
+		// custom types decode themselves
+		return decodeTreeJSON(tree, fv)
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if fv.IsNil() {
+			fv.Set(reflect.New(t.Elem()))
+		}
+		return decodeTreeValue(tree, fv.Elem())
+	case reflect.Struct:
+		obj, ok := tree.(map[string]interface{})
+		if !ok && treeTypes[t] {
+			// encoding/json would call UnmarshalJSON of the type coming back here
+			return fmt.Errorf("%s must be an object, got %s", t.Name(), jsonKind(tree))
+		}
+		if !ok {
+			break
+		}
+		for _, f := range structFields(t) {
+			value, ok := lookupKey(obj, f.name)
+			if !ok {
+				continue
+			}
+			if err := decodeTreeValue(value, fv.Field(f.index)); err != nil {
+				return fmt.Errorf("%s: %w", f.name, err)
+			}
+		}
+		return nil
+	case reflect.Slice:
+		items, ok := tree.([]interface{})
+		if !ok || t.Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		res := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			if err := decodeTreeValue(item, res.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		fv.Set(res)
+		return nil
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			fv.Set(reflect.ValueOf(plainTree(tree)))
+			return nil
+		}
+	case reflect.String:
+		if s, ok := tree.(string); ok {
+			fv.SetString(s)
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := tree.(bool); ok {
+			fv.SetBool(b)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		f, ok := tree.(float64)
+		if n, isNumber := tree.(json.Number); isNumber {
+			var err error
+			f, err = n.Float64()
+			ok = err == nil
+		}
+		if ok && !fv.OverflowFloat(f) {
+			fv.SetFloat(f)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := tree.(json.Number); ok {
+			if i, err := n.Int64(); err == nil && !fv.OverflowInt(i) {
+				fv.SetInt(i)
+				return nil
+			}
+		}
+		// float is compared before converting, out of range conversion is undefined
+		if f, ok := tree.(float64); ok && f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !fv.OverflowInt(int64(f)) {
+			fv.SetInt(int64(f))
+			return nil
+		}
+	}
+	// type mismatches and the rest are left to encoding/json with its errors
+	return decodeTreeJSON(tree, fv)
+}
+
+// decodeTreeJSON decodes the tree through JSON
+func decodeTreeJSON(tree interface{}, fv reflect.Value) error {
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, fv.Addr().Interface())
+}
+
+// plainTree converts json.Number values of the tree to float64 like json.Unmarshal does for interface{}
+func plainTree(tree interface{}) interface{} {
+	switch t := tree.(type) {
+	case json.Number:
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		for k, v := range t {
+			t[k] = plainTree(v)
+		}
+	case []interface{}:
+		for i, v := range t {
+			t[i] = plainTree(v)
+		}
+	}
+	return tree
+}
+
+// jsonKind returns JSON name of the tree value type
+func jsonKind(tree interface{}) string {
+	switch tree.(type) {
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	}
+	return fmt.Sprintf("%T", tree)
+}
+
+// lookupKey finds object value preferring exact key match
+// and falling back to case-insensitive one like encoding/json does
+func lookupKey(obj map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := obj[name]; ok {
+		return v, true
+	}
+	for k, v := range obj {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// structFieldInfo is exported struct field with its JSON name
+type structFieldInfo struct {
+	index int
+	name  string
+}
+
+var structFieldsCache sync.Map // reflect.Type -> []structFieldInfo
+
+// structFields returns decodable fields of struct type
+func structFields(t reflect.Type) []structFieldInfo {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]structFieldInfo)
+	}
+	var fields []structFieldInfo
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		if name := jsonFieldName(field); name != "" {
+			fields = append(fields, structFieldInfo{index: i, name: name})
+		}
+	}
+	structFieldsCache.Store(t, fields)
+	return fields
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
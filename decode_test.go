@@ -0,0 +1,98 @@
+package cards
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	for _, path := range []string{
+		"./test/actionSet.json",
+		"./test/background.json",
+		"./test/example.json",
+		"./test/fallback.json",
+		"./test/images.json",
+		"./test/inputs.json",
+		"./test/media.json",
+		"./test/rich.json",
+		"./test/toggle.json",
+	} {
+		cardJSON := mustReadFile(path)
+		c, err := Parse(strings.NewReader(cardJSON))
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		got, err := c.StringIndent("", "  ")
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if got != cardJSON {
+			t.Errorf("%s: expected:\n%s\nbut got:\n%s", path, cardJSON, got)
+		}
+	}
+}
+
+func TestParseConcreteTypes(t *testing.T) {
+	c, err := Parse(strings.NewReader(mustReadFile("./test/example.json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	container, ok := c.Body[0].(*Container)
+	if !ok {
+		t.Fatalf("expected *Container, got %T", c.Body[0])
+	}
+	if _, ok := container.Items[1].(*ColumnSet); !ok {
+		t.Errorf("expected *ColumnSet, got %T", container.Items[1])
+	}
+	showCard, ok := c.Actions[0].(*ActionShowCard)
+	if !ok {
+		t.Fatalf("expected *ActionShowCard, got %T", c.Actions[0])
+	}
+	if _, ok := showCard.Card.Body[0].(*InputText); !ok {
+		t.Errorf("expected *InputText, got %T", showCard.Card.Body[0])
+	}
+}
+
+func TestUnmarshalUnknownType(t *testing.T) {
+	var c Card
+	err := json.Unmarshal([]byte(`{"type":"AdaptiveCard","version":"1.0","body":[{"type":"Foo"}]}`), &c)
+	if err == nil {
+		t.Error("expected to have an error, got nil")
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, data := range []string{
+		`[1]`,
+		`"card"`,
+		`{"type":"AdaptiveCard","body":{"type":"TextBlock"}}`,
+		`{"type":"AdaptiveCard","body":[{"type":"ColumnSet","columns":[1]}]}`,
+		`{"type":"AdaptiveCard","body":[{"type":"TextBlock","text":1}]}`,
+		`{"type":"AdaptiveCard","body":[{"type":"TextBlock","maxLines":1.5}]}`,
+		`{"type":"AdaptiveCard","body":[{"type":"TextBlock","fallback":"keep"}]}`,
+		`{"type":"AdaptiveCard","body":[{"type":"TextBlock","fallback":1}]}`,
+	} {
+		var c Card
+		if err := json.Unmarshal([]byte(data), &c); err == nil {
+			t.Errorf("%s: expected to have an error, got nil", data)
+		}
+	}
+}
+
+func TestUnmarshalNumbers(t *testing.T) {
+	var c Card
+	data := `{"type":"AdaptiveCard","body":[{"type":"Input.Text","id":"a","maxLength":9007199254740993}],` +
+		`"actions":[{"type":"Action.Submit","data":{"n":1.5,"list":[2]}}]}`
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Body[0].(*InputText).MaxLength; got != 9007199254740993 {
+		t.Errorf("expected integer to be decoded exactly, got %d", got)
+	}
+	expected := map[string]interface{}{"n": 1.5, "list": []interface{}{float64(2)}}
+	if got := c.Actions[0].(*ActionSubmit).Data; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,181 @@
+package cards
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Warning describes a change made to the card by a transformation
+type Warning struct {
+	Path    string // JSON pointer of the element in the original card
+	Type    string // element type
+	ID      string // element id if any
+	Message string
+}
+
+func (w Warning) String() string {
+	if w.Path == "" {
+		return w.Message
+	}
+	return w.Path + ": " + w.Message
+}
+
+// Downlevel returns copy of the card rewritten to the target version:
+// elements newer than the target are replaced with their fallback or dropped,
+// properties newer than the target are removed, input labels are converted
+// to preceding TextBlocks and card version is lowered to the target.
+// Returned warnings describe every change. The card itself is not modified.
+func (c *Card) Downlevel(target string) (*Card, []Warning) {
+	res := c.Clone()
+	d := &downleveler{target: target}
+	if _, _, err := parseVersion(target); err != nil {
+		d.warn("", nil, err.Error())
+		return res, d.warnings
+	}
+	if !isNewer(c.Version, target) {
+		return res, nil
+	}
+	d.stripProperties("", AdaptiveCardType, nil, res)
+	d.children(res, "")
+	res.Version = target
+	return res, d.warnings
+}
+
+type downleveler struct {
+	target   string
+	warnings []Warning
+}
+
+func (d *downleveler) warn(path string, n Node, msg string) {
+	w := Warning{Path: path, Message: msg}
+	if n != nil {
+		w.Type = n.NodeType()
+		w.ID = nodeID(n)
+	}
+	d.warnings = append(d.warnings, w)
+}
+
+// children downlevels all child elements of the parent
+func (d *downleveler) children(parent interface{}, path string) {
+	for _, s := range slots(parent) {
+		slotPath := path + "/" + s.name
+		switch {
+		case s.list != nil:
+			*s.list = d.list(*s.list, slotPath)
+		case s.node != nil:
+			*s.node = d.single(*s.node, slotPath)
+		default:
+			for i, n := range s.nodes {
+				if !isNilNode(n) {
+					d.element(n, fmt.Sprintf("%s/%d", slotPath, i))
+				}
+			}
+		}
+	}
+}
+
+func (d *downleveler) list(nodes []Node, path string) []Node {
+	if nodes == nil {
+		return nil
+	}
+	res := make([]Node, 0, len(nodes))
+	for i, n := range nodes {
+		nodePath := fmt.Sprintf("%s/%d", path, i)
+		for _, r := range d.replace(n, nodePath) {
+			if label := d.labelBlock(r, nodePath); label != nil {
+				res = append(res, label)
+			}
+			d.element(r, nodePath)
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+func (d *downleveler) single(n Node, path string) Node {
+	replaced := d.replace(n, path)
+	if len(replaced) == 0 {
+		return nil
+	}
+	if len(replaced) > 1 {
+		d.warn(path, n, "only the first fallback element is kept")
+	}
+	d.element(replaced[0], path)
+	return replaced[0]
+}
+
+// replace returns the element itself if it's supported by the target version,
+// otherwise its supported fallback elements
+func (d *downleveler) replace(n Node, path string) []Node {
+	if isNilNode(n) {
+		return nil
+	}
+	v := ElementVersion(n.NodeType())
+	if !isNewer(v, d.target) {
+		return []Node{n}
+	}
+	f := nodeFallback(n)
+	if f == nil || f.Element == nil {
+		d.warn(path, n, fmt.Sprintf("%s requires version %s, element is dropped", n.NodeType(), v))
+		return nil
+	}
+	d.warn(path, n, fmt.Sprintf("%s requires version %s, element is replaced with its fallback", n.NodeType(), v))
+	return d.replace(f.Element, path+"/fallback")
+}
+
+// element downlevels properties and children of supported element
+func (d *downleveler) element(n Node, path string) {
+	d.stripProperties(path, n.NodeType(), n, n)
+	if sc, ok := n.(*ActionShowCard); ok {
+		d.stripProperties(path+"/card", AdaptiveCardType, n, &sc.Card)
+	}
+	d.children(n, path)
+}
+
+// stripProperties clears properties of v which are newer than the target version
+func (d *downleveler) stripProperties(path, typeName string, n Node, v interface{}) {
+	for _, prop := range setProperties(v) {
+		pv := PropertyVersion(typeName, prop)
+		if !isNewer(pv, d.target) {
+			continue
+		}
+		if clearProperty(v, prop) {
+			d.warn(path, n, fmt.Sprintf("property %q requires version %s and is removed", prop, pv))
+		}
+	}
+}
+
+// labelBlock returns TextBlock replacing input label if labels are not supported by the target version
+func (d *downleveler) labelBlock(n Node, path string) Node {
+	label := structField(n, "Label")
+	if !label.IsValid() || label.Kind() != reflect.String || label.String() == "" {
+		return nil
+	}
+	if !isNewer(PropertyVersion(n.NodeType(), "label"), d.target) {
+		return nil
+	}
+	d.warn(path, n, "label is converted to TextBlock")
+	return &TextBlock{
+		Type: TextBlockType,
+		Text: label.String(),
+		Wrap: TruePtr(),
+	}
+}
+
+// clearProperty sets field with JSON name prop to zero value
+func clearProperty(v interface{}, prop string) bool {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	rv = rv.Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if field.PkgPath != "" || jsonFieldName(field) != prop {
+			continue
+		}
+		rv.Field(i).Set(reflect.Zero(field.Type))
+		return true
+	}
+	return false
+}
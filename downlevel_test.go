@@ -0,0 +1,85 @@
+package cards
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDownlevel(t *testing.T) {
+	c := New([]Node{
+		&TextBlock{Text: "foo", Height: HeightStretch},
+		&Media{
+			Sources:  []*MediaSource{{MimeType: "video/mp4", URL: "https://adaptivecards.io/content/cat.mp4"}},
+			Fallback: FallbackElement(&Image{URL: "https://adaptivecards.io/content/cat.png"}),
+		},
+		&RichTextBlock{Inlines: []*TextRun{{Text: "dropped"}}},
+		&InputText{ID: "name", Label: "Name", IsRequired: TruePtr()},
+	}, []Node{
+		&ActionToggleVisibility{Title: "Toggle", TargetElements: []TargetElement{{ElementID: "name"}}},
+	}).WithVersion(Version13)
+	if err := c.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+	original, err := c.String()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, warnings := c.Downlevel(Version1)
+	var got []string
+	for _, w := range warnings {
+		got = append(got, w.String())
+	}
+	expected := []string{
+		`/body/0: property "height" requires version 1.1 and is removed`,
+		`/body/1: Media requires version 1.1, element is replaced with its fallback`,
+		`/body/2: RichTextBlock requires version 1.2, element is dropped`,
+		`/body/3: label is converted to TextBlock`,
+		`/body/3: property "isRequired" requires version 1.3 and is removed`,
+		`/body/3: property "label" requires version 1.3 and is removed`,
+		`/actions/0: Action.ToggleVisibility requires version 1.2, element is dropped`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%v\nbut got:\n%v", expected, got)
+	}
+
+	if res.Version != Version1 {
+		t.Errorf("expected version %s, got %s", Version1, res.Version)
+	}
+	if errs := res.ValidateVersion(); len(errs) != 0 {
+		t.Errorf("expected no version errors, got %s", errs)
+	}
+	types := []string{}
+	for _, n := range res.Body {
+		types = append(types, n.NodeType())
+	}
+	expectedTypes := []string{TextBlockType, ImageType, TextBlockType, InputTextType}
+	if !reflect.DeepEqual(types, expectedTypes) {
+		t.Errorf("expected body %v, got %v", expectedTypes, types)
+	}
+	if label := res.Body[2].(*TextBlock); label.Text != "Name" {
+		t.Errorf("expected label TextBlock, got %q", label.Text)
+	}
+	if len(res.Actions) != 0 {
+		t.Errorf("expected no actions, got %d", len(res.Actions))
+	}
+
+	after, err := c.String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after != original {
+		t.Error("original card is modified")
+	}
+}
+
+func TestDownlevelSameVersion(t *testing.T) {
+	c := New([]Node{&TextBlock{Text: "foo"}}, nil).WithVersion(Version12)
+	res, warnings := c.Downlevel(Version13)
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+	if res == c || res.Version != Version12 {
+		t.Error("expected unchanged copy of the card")
+	}
+}
@@ -0,0 +1,181 @@
+package cards
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrElementNotFound is returned by editing methods when there is no element with the ID
+	ErrElementNotFound = errors.New("element is not found")
+	// ErrAmbiguousID is returned by editing methods when several elements have the ID
+	ErrAmbiguousID = errors.New("element id is ambiguous")
+)
+
+// location is a place of an element in its parent slot
+type location struct {
+	parent interface{}
+	slot   slot
+	index  int // index in list or typed list, -1 for single element slot
+	path   string
+	node   Node
+}
+
+// list returns elements of the location slot
+func (l location) list() []Node {
+	if l.slot.list != nil {
+		return *l.slot.list
+	}
+	return l.slot.nodes
+}
+
+// setList replaces elements of the location slot
+func (l location) setList(nodes []Node) error {
+	if l.slot.list != nil {
+		*l.slot.list = nodes
+		return nil
+	}
+	old := l.slot.nodes
+	if !l.slot.setNodes(nodes) {
+		l.slot.setNodes(old)
+		return fmt.Errorf("%s of %s only accept elements of the same type", l.slot.name, l.parent.(Node).NodeType())
+	}
+	return nil
+}
+
+// locate returns the place of the only element with the ID. Elements of fallbacks are not searched.
+func locate(root interface{}, id string) (location, error) {
+	var found []location
+	var search func(parent interface{}, path string)
+	search = func(parent interface{}, path string) {
+		for _, s := range slots(parent) {
+			if s.name == "fallback" {
+				continue
+			}
+			slotPath := path + "/" + s.name
+			var nodes []Node
+			switch {
+			case s.list != nil:
+				nodes = *s.list
+			case s.node != nil:
+				if !isNilNode(*s.node) {
+					if nodeID(*s.node) == id {
+						found = append(found, location{parent: parent, slot: s, index: -1, path: slotPath, node: *s.node})
+					}
+					search(*s.node, slotPath)
+				}
+				continue
+			default:
+				nodes = s.nodes
+			}
+			for i, n := range nodes {
+				if isNilNode(n) {
+					continue
+				}
+				nodePath := fmt.Sprintf("%s/%d", slotPath, i)
+				if nodeID(n) == id {
+					found = append(found, location{parent: parent, slot: s, index: i, path: nodePath, node: n})
+				}
+				search(n, nodePath)
+			}
+		}
+	}
+	if id != "" {
+		search(root, "")
+	}
+	switch len(found) {
+	case 0:
+		return location{}, fmt.Errorf("%w: %q", ErrElementNotFound, id)
+	case 1:
+		return found[0], nil
+	}
+	paths := make([]string, 0, len(found))
+	for _, l := range found {
+		paths = append(paths, l.path)
+	}
+	return location{}, fmt.Errorf("%w: %q is used at %s", ErrAmbiguousID, id, strings.Join(paths, ", "))
+}
+
+// ReplaceByID replaces the element with the ID with n
+func (c *Card) ReplaceByID(id string, n Node) error {
+	if isNilNode(n) {
+		return errors.New("replacement element is nil")
+	}
+	l, err := locate(c, id)
+	if err != nil {
+		return err
+	}
+	if l.index < 0 {
+		*l.slot.node = n
+		return nil
+	}
+	nodes := append([]Node{}, l.list()...)
+	nodes[l.index] = n
+	return l.setList(nodes)
+}
+
+// RemoveByID removes the element with the ID
+func (c *Card) RemoveByID(id string) error {
+	l, err := locate(c, id)
+	if err != nil {
+		return err
+	}
+	if l.index < 0 {
+		*l.slot.node = nil
+		return nil
+	}
+	nodes := append([]Node{}, l.list()[:l.index]...)
+	return l.setList(append(nodes, l.list()[l.index+1:]...))
+}
+
+// InsertBefore inserts elements before the element with the ID in the list holding it
+func (c *Card) InsertBefore(id string, nodes ...Node) error {
+	return c.insert(id, 0, nodes)
+}
+
+// InsertAfter inserts elements after the element with the ID in the list holding it
+func (c *Card) InsertAfter(id string, nodes ...Node) error {
+	return c.insert(id, 1, nodes)
+}
+
+func (c *Card) insert(id string, offset int, nodes []Node) error {
+	for _, n := range nodes {
+		if isNilNode(n) {
+			return errors.New("inserted element is nil")
+		}
+	}
+	l, err := locate(c, id)
+	if err != nil {
+		return err
+	}
+	if l.index < 0 {
+		return fmt.Errorf("element %q at %s is not in a list", id, l.path)
+	}
+	at := l.index + offset
+	res := append([]Node{}, l.list()[:at]...)
+	res = append(res, nodes...)
+	return l.setList(append(res, l.list()[at:]...))
+}
+
+// AppendToContainer appends elements to the element with the ID: items of Container and Column,
+// actions of ActionSet, body of Action.ShowCard card, columns of ColumnSet or images of ImageSet
+func (c *Card) AppendToContainer(id string, nodes ...Node) error {
+	for _, n := range nodes {
+		if isNilNode(n) {
+			return errors.New("appended element is nil")
+		}
+	}
+	l, err := locate(c, id)
+	if err != nil {
+		return err
+	}
+	for _, s := range elementSlots(l.node) {
+		if s.node != nil {
+			continue
+		}
+		target := location{parent: l.node, slot: s}
+		return target.setList(append(append([]Node{}, target.list()...), nodes...))
+	}
+	return fmt.Errorf("element %q is %s which doesn't hold elements", id, l.node.NodeType())
+}
@@ -0,0 +1,196 @@
+package cards
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func editTestCard() *Card {
+	return New([]Node{
+		&TextBlock{ID: "title", Text: "Vote"},
+		&Container{ID: "box", Items: []Node{
+			&ActionSet{ID: "buttons", Actions: []Node{
+				&ActionSubmit{ID: "yes", Title: "Yes"},
+				&ActionSubmit{ID: "no", Title: "No"},
+			}},
+		}},
+		&ColumnSet{ID: "columns", Columns: []*Column{{ID: "left", Items: []Node{}}}},
+		&Media{ID: "video", Fallback: FallbackElement(&TextBlock{ID: "title", Text: "No video"})},
+	}, []Node{
+		&ActionShowCard{ID: "more", Title: "More", Card: NestedCard{
+			Body: []Node{&InputText{ID: "comment"}},
+		}},
+	})
+}
+
+func bodyIDs(nodes []Node) []string {
+	res := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, nodeID(n))
+	}
+	return res
+}
+
+func TestReplaceByID(t *testing.T) {
+	c := editTestCard()
+	banner := &TextBlock{ID: "banner", Text: "Approved by X"}
+	if err := c.ReplaceByID("buttons", banner); err != nil {
+		t.Fatal(err)
+	}
+	if items := c.Body[1].(*Container).Items; len(items) != 1 || items[0] != banner {
+		t.Errorf("expected buttons to be replaced but got %v", items)
+	}
+	if err := c.ReplaceByID("comment", &InputNumber{ID: "score"}); err != nil {
+		t.Fatal(err)
+	}
+	if body := c.Actions[0].(*ActionShowCard).Card.Body; body[0].(*InputNumber).ID != "score" {
+		t.Errorf("expected show card input to be replaced but got %v", body)
+	}
+	right := &Column{ID: "right"}
+	if err := c.ReplaceByID("left", right); err != nil {
+		t.Fatal(err)
+	}
+	if cols := c.Body[2].(*ColumnSet).Columns; len(cols) != 1 || cols[0] != right {
+		t.Errorf("expected column to be replaced but got %v", cols)
+	}
+	err := c.ReplaceByID("right", &TextBlock{Text: "not a column"})
+	if err == nil || c.Body[2].(*ColumnSet).Columns[0] != right {
+		t.Errorf("expected column replacement by text to fail and keep columns, got %v", err)
+	}
+	if err := c.ReplaceByID("title", nil); err == nil {
+		t.Error("expected error for nil replacement")
+	}
+}
+
+func TestRemoveByID(t *testing.T) {
+	c := editTestCard()
+	for _, id := range []string{"yes", "columns", "more"} {
+		if err := c.RemoveByID(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ids := bodyIDs(c.Body); !reflect.DeepEqual(ids, []string{"title", "box", "video"}) {
+		t.Errorf("unexpected body %v", ids)
+	}
+	if ids := bodyIDs(c.Body[1].(*Container).Items[0].(*ActionSet).Actions); !reflect.DeepEqual(ids, []string{"no"}) {
+		t.Errorf("unexpected actions %v", ids)
+	}
+	if len(c.Actions) != 0 {
+		t.Errorf("expected show card to be removed but got %v", c.Actions)
+	}
+
+	c = New([]Node{&Image{URL: "a.png", SelectAction: &ActionOpenURL{ID: "open", URL: "https://example.com"}}}, nil)
+	if err := c.RemoveByID("open"); err != nil {
+		t.Fatal(err)
+	}
+	if c.Body[0].(*Image).SelectAction != nil {
+		t.Error("expected select action to be removed")
+	}
+}
+
+func TestEditKeepsCallerSlices(t *testing.T) {
+	columns := []*Column{{ID: "a"}, {ID: "b"}}
+	images := []*Image{{ID: "x", URL: "x.png"}, {ID: "y", URL: "y.png"}}
+	c := New([]Node{
+		&ColumnSet{Columns: columns},
+		&ImageSet{Images: images},
+	}, nil)
+	for _, id := range []string{"a", "x"} {
+		if err := c.RemoveByID(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.InsertBefore("b", &Column{ID: "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if columns[0].ID != "a" || columns[1].ID != "b" {
+		t.Errorf("expected caller columns to be kept, got %v %v", columns[0].ID, columns[1].ID)
+	}
+	if images[0].ID != "x" || images[1].ID != "y" {
+		t.Errorf("expected caller images to be kept, got %v %v", images[0].ID, images[1].ID)
+	}
+	if cols := c.Body[0].(*ColumnSet).Columns; len(cols) != 2 || cols[0].ID != "c" || cols[1].ID != "b" {
+		t.Errorf("unexpected columns %v", cols)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	c := editTestCard()
+	if err := c.InsertBefore("box", &TextBlock{ID: "a", Text: "a"}, &TextBlock{ID: "b", Text: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InsertAfter("video", &TextBlock{ID: "z", Text: "z"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InsertAfter("no", &ActionSubmit{ID: "maybe", Title: "Maybe"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InsertBefore("left", &Column{ID: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	if ids := bodyIDs(c.Body); !reflect.DeepEqual(ids, []string{"title", "a", "b", "box", "columns", "video", "z"}) {
+		t.Errorf("unexpected body %v", ids)
+	}
+	if ids := bodyIDs(c.Body[3].(*Container).Items[0].(*ActionSet).Actions); !reflect.DeepEqual(ids, []string{"yes", "no", "maybe"}) {
+		t.Errorf("unexpected actions %v", ids)
+	}
+	if cols := c.Body[4].(*ColumnSet).Columns; len(cols) != 2 || cols[0].ID != "first" {
+		t.Errorf("unexpected columns %v", cols)
+	}
+	if err := c.InsertBefore("left", &TextBlock{Text: "x"}); err == nil || len(c.Body[4].(*ColumnSet).Columns) != 2 {
+		t.Errorf("expected inserting text into columns to fail, got %v", err)
+	}
+
+	c = New([]Node{&Image{URL: "a.png", SelectAction: &ActionOpenURL{ID: "open", URL: "https://example.com"}}}, nil)
+	if err := c.InsertAfter("open", &ActionSubmit{}); err == nil || !strings.Contains(err.Error(), "not in a list") {
+		t.Errorf("expected error for single element slot but got %v", err)
+	}
+}
+
+func TestAppendToContainer(t *testing.T) {
+	c := editTestCard()
+	for id, n := range map[string]Node{
+		"box":     &TextBlock{ID: "note", Text: "note"},
+		"left":    &TextBlock{ID: "cell", Text: "cell"},
+		"buttons": &ActionSubmit{ID: "later", Title: "Later"},
+		"more":    &InputToggle{ID: "agree", Title: "Agree"},
+		"columns": &Column{ID: "right"},
+	} {
+		if err := c.AppendToContainer(id, n); err != nil {
+			t.Fatal(err)
+		}
+		if found, _ := FindByID(c, nodeID(n)); found != n {
+			t.Errorf("expected %s to be appended to %s", nodeID(n), id)
+		}
+	}
+	if body := c.Actions[0].(*ActionShowCard).Card.Body; len(body) != 2 {
+		t.Errorf("expected toggle appended to show card body but got %v", body)
+	}
+	if err := c.AppendToContainer("title", &TextBlock{Text: "x"}); err == nil {
+		t.Error("expected error for TextBlock")
+	}
+	if err := c.AppendToContainer("columns", &TextBlock{Text: "x"}); err == nil {
+		t.Error("expected error for appending text to columns")
+	}
+}
+
+func TestEditErrors(t *testing.T) {
+	c := editTestCard()
+	// elements of fallbacks are not searched so the title isn't ambiguous
+	if err := c.ReplaceByID("title", &TextBlock{ID: "title", Text: "Done"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RemoveByID("missing"); !errors.Is(err, ErrElementNotFound) {
+		t.Errorf("expected not found error but got %v", err)
+	}
+	if err := c.RemoveByID(""); !errors.Is(err, ErrElementNotFound) {
+		t.Errorf("expected not found error for empty id but got %v", err)
+	}
+	c.Body = append(c.Body, &Container{ID: "box"})
+	err := c.AppendToContainer("box", &TextBlock{Text: "x"})
+	if !errors.Is(err, ErrAmbiguousID) || !strings.Contains(err.Error(), "/body/1, /body/4") {
+		t.Errorf("expected ambiguous error with paths but got %v", err)
+	}
+}
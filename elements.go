@@ -1,66 +1,94 @@
 package cards
 
-import (
-	"errors"
-)
-
 // TextBlock is textblock element
 type TextBlock struct {
-	Type                string `json:"type"` // required
-	Text                string `json:"text"` // required
-	Color               string `json:"color,omitempty"`
-	FontType            string `json:"fontType,omitempty"` // FIXME this is a special type in a.c.
-	HorizontalAlignment string `json:"horizontalAlignment,omitempty"`
-	IsSubtle            *bool  `json:"isSubtle,omitempty"`
-	MaxLines            int64  `json:"maxLines,omitempty"`
-	Size                string `json:"size,omitempty"`
-	Weight              string `json:"weight,omitempty"`
-	Wrap                *bool  `json:"wrap,omitempty"`
+	Type                string              `json:"type"` // required
+	Text                string              `json:"text"` // required
+	Color               Color               `json:"color,omitempty"`
+	FontType            FontType            `json:"fontType,omitempty"`
+	HorizontalAlignment HorizontalAlignment `json:"horizontalAlignment,omitempty"`
+	IsSubtle            *bool               `json:"isSubtle,omitempty"`
+	MaxLines            int64               `json:"maxLines,omitempty"`
+	Size                TextSize            `json:"size,omitempty"`
+	Weight              FontWeight          `json:"weight,omitempty"`
+	Wrap                *bool               `json:"wrap,omitempty"`
 	// inherited
-	Fallback  []Node            `json:"fallback,omitempty"`
-	Height    string            `json:"height,omitempty"`
-	Separator *bool             `json:"separator,omitempty"`
-	Spacing   string            `json:"spacing,omitempty"`
-	ID        string            `json:"id,omitempty"`
-	IsVisible *bool             `json:"isVisible,omitempty"`
-	Requires  map[string]string `json:"requires,omitempty"`
+	Fallback  *Fallback          `json:"fallback,omitempty"`
+	Height    BlockElementHeight `json:"height,omitempty"`
+	Separator *bool              `json:"separator,omitempty"`
+	Spacing   Spacing            `json:"spacing,omitempty"`
+	ID        string             `json:"id,omitempty"`
+	IsVisible *bool              `json:"isVisible,omitempty"`
+	Requires  map[string]string  `json:"requires,omitempty"`
+}
+
+// NodeType returns TextBlock element type
+func (n *TextBlock) NodeType() string {
+	return TextBlockType
 }
 
-func (n *TextBlock) prepare() error {
+// Prepare sets TextBlock type and validates its fields
+func (n *TextBlock) Prepare() error {
 	n.Type = TextBlockType
+	return check(n)
+}
+
+func (n *TextBlock) check(r *reporter) {
 	if n.Text == "" {
-		return errors.New("TextBlock text is required")
+		r.required("TextBlock text is required")
 	}
-	return nil
+	r.enum("color", n.Color)
+	r.enum("fontType", n.FontType)
+	r.enum("horizontalAlignment", n.HorizontalAlignment)
+	r.enum("size", n.Size)
+	r.enum("weight", n.Weight)
+	r.layout(n.Height, n.Spacing)
 }
 
 // Image is Image element.
 type Image struct {
-	Type                string `json:"type"` // required, must be "Image"
-	URL                 string `json:"url"`  // required
-	AltText             string `json:"altText,omitempty"`
-	BackgroundColor     string `json:"backgroundColor,omitempty"`
-	Height              string `json:"height,omitempty"` // default "auto"
-	HorizontalAlignment string `json:"horizontalAlignment,omitempty"`
-	SelectAction        Node   `json:"selectAction,omitempty"`
-	Size                string `json:"size,omitempty"`
-	Style               string `json:"style,omitempty"` // "default" or "person"
-	Width               string `json:"width,omitempty"`
+	Type                string              `json:"type"` // required, must be "Image"
+	URL                 string              `json:"url"`  // required
+	AltText             string              `json:"altText,omitempty"`
+	BackgroundColor     string              `json:"backgroundColor,omitempty"`
+	Height              BlockElementHeight  `json:"height,omitempty"` // default "auto"
+	HorizontalAlignment HorizontalAlignment `json:"horizontalAlignment,omitempty"`
+	SelectAction        Node                `json:"selectAction,omitempty"`
+	Size                ImageSize           `json:"size,omitempty"`
+	Style               ImageStyle          `json:"style,omitempty"` // "default" or "person"
+	Width               string              `json:"width,omitempty"`
 	// inherited
-	Fallback  []Node            `json:"fallback,omitempty"`
+	Fallback  *Fallback         `json:"fallback,omitempty"`
 	Separator *bool             `json:"separator,omitempty"`
-	Spacing   string            `json:"spacing,omitempty"`
+	Spacing   Spacing           `json:"spacing,omitempty"`
 	ID        string            `json:"id,omitempty"`
 	IsVisible *bool             `json:"isVisible,omitempty"`
 	Requires  map[string]string `json:"requires,omitempty"`
 }
 
-func (n *Image) prepare() error {
+// NodeType returns Image element type
+func (n *Image) NodeType() string {
+	return ImageType
+}
+
+// Prepare sets Image type and validates its fields
+func (n *Image) Prepare() error {
 	n.Type = ImageType
+	return check(n)
+}
+
+func (n *Image) check(r *reporter) {
 	if n.URL == "" {
-		return errors.New("Image url is required")
+		r.required("Image url is required")
 	}
-	return nil
+	if !n.Height.IsValid() {
+		r.pixels("height", string(n.Height))
+	}
+	r.pixels("width", n.Width)
+	r.enum("horizontalAlignment", n.HorizontalAlignment)
+	r.enum("size", n.Size)
+	r.enum("style", n.Style)
+	r.enum("spacing", n.Spacing)
 }
 
 // Media displays a media player for audio or video content.
@@ -70,26 +98,34 @@ type Media struct {
 	Poster  string         `json:"poster,omitempty"`
 	AltText string         `json:"altText,omitempty"`
 	// inherited
-	Fallback  []Node            `json:"fallback,omitempty"`
-	Height    string            `json:"height,omitempty"`
-	Separator *bool             `json:"separator,omitempty"`
-	Spacing   string            `json:"spacing,omitempty"`
-	ID        string            `json:"id,omitempty"`
-	IsVisible *bool             `json:"isVisible,omitempty"`
-	Requires  map[string]string `json:"requires,omitempty"`
+	Fallback  *Fallback          `json:"fallback,omitempty"`
+	Height    BlockElementHeight `json:"height,omitempty"`
+	Separator *bool              `json:"separator,omitempty"`
+	Spacing   Spacing            `json:"spacing,omitempty"`
+	ID        string             `json:"id,omitempty"`
+	IsVisible *bool              `json:"isVisible,omitempty"`
+	Requires  map[string]string  `json:"requires,omitempty"`
 }
 
-func (n *Media) prepare() error {
+// NodeType returns Media element type
+func (n *Media) NodeType() string {
+	return MediaType
+}
+
+// Prepare sets Media type and validates its fields and sources
+func (n *Media) Prepare() error {
 	n.Type = MediaType
+	return check(n)
+}
+
+func (n *Media) check(r *reporter) {
 	if len(n.Sources) < 1 {
-		return errors.New("Media must have sources")
+		r.required("Media must have sources")
 	}
-	for _, s := range n.Sources {
-		if err := s.prepare(); err != nil {
-			return err
-		}
+	r.layout(n.Height, n.Spacing)
+	for i, s := range n.Sources {
+		s.check(r.at("sources/%d", i))
 	}
-	return nil
 }
 
 // MediaSource defines a source for a Media element.
@@ -98,64 +134,90 @@ type MediaSource struct {
 	URL      string `json:"url"`      // required
 }
 
-func (m *MediaSource) prepare() error {
+func (m *MediaSource) check(r *reporter) {
 	if m.MimeType == "" {
-		return errors.New("MediaSource must have mime type")
+		r.required("MediaSource must have mime type")
 	}
 	if m.URL == "" {
-		return errors.New("MediaSource must have url")
+		r.required("MediaSource must have url")
 	}
-	return nil
 }
 
 // RichTextBlock defines an array of inlines, allowing for inline text formatting.
 type RichTextBlock struct {
-	Type                string     `json:"type"`    // required, must be RichTextBlock
-	Inlines             []*TextRun `json:"inlines"` // required
-	HorizontalAlignment string     `json:"horizontalAlignment,omitempty"`
+	Type                string              `json:"type"`    // required, must be RichTextBlock
+	Inlines             []*TextRun          `json:"inlines"` // required
+	HorizontalAlignment HorizontalAlignment `json:"horizontalAlignment,omitempty"`
 	// inherited
-	Fallback  []Node            `json:"fallback,omitempty"`
-	Height    string            `json:"height,omitempty"`
-	Separator *bool             `json:"separator,omitempty"`
-	Spacing   string            `json:"spacing,omitempty"`
-	ID        string            `json:"id,omitempty"`
-	IsVisible *bool             `json:"isVisible,omitempty"`
-	Requires  map[string]string `json:"requires,omitempty"`
+	Fallback  *Fallback          `json:"fallback,omitempty"`
+	Height    BlockElementHeight `json:"height,omitempty"`
+	Separator *bool              `json:"separator,omitempty"`
+	Spacing   Spacing            `json:"spacing,omitempty"`
+	ID        string             `json:"id,omitempty"`
+	IsVisible *bool              `json:"isVisible,omitempty"`
+	Requires  map[string]string  `json:"requires,omitempty"`
 }
 
-func (n *RichTextBlock) prepare() error {
+// NodeType returns RichTextBlock element type
+func (n *RichTextBlock) NodeType() string {
+	return RichTextBlockType
+}
+
+// Prepare sets RichTextBlock type, validates its fields and prepares its inlines
+func (n *RichTextBlock) Prepare() error {
 	n.Type = RichTextBlockType
-	if len(n.Inlines) < 1 {
-		return errors.New("RichTextBlock must have inlines")
+	if err := check(n); err != nil {
+		return err
 	}
 	for _, i := range n.Inlines {
-		if err := i.prepare(); err != nil {
+		if err := i.Prepare(); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (n *RichTextBlock) check(r *reporter) {
+	if len(n.Inlines) < 1 {
+		r.required("RichTextBlock must have inlines")
+	}
+	r.enum("horizontalAlignment", n.HorizontalAlignment)
+	r.layout(n.Height, n.Spacing)
+}
+
 // TextRun defines a single run of formatted text.
 type TextRun struct {
-	Type          string `json:"type"` // required
-	Text          string `json:"text"` // required
-	Color         string `json:"color,omitempty"`
-	FontType      string `json:"fontType,omitempty"` // FIXME this is a special type in a.c.
-	Highlight     *bool  `json:"highlight,omitempty"`
-	IsSubtle      *bool  `json:"isSubtle,omitempty"`
-	Italic        *bool  `json:"italic,omitempty"`
-	SelectAction  Node   `json:"selectAction,omitempty"`
-	Size          string `json:"size,omitempty"`
-	Strikethrough *bool  `json:"strikethrough,omitempty"`
-	Underline     *bool  `json:"underline,omitempty"`
-	Weight        string `json:"weight,omitempty"`
-}
-
-func (t *TextRun) prepare() error {
+	Type          string     `json:"type"` // required
+	Text          string     `json:"text"` // required
+	Color         Color      `json:"color,omitempty"`
+	FontType      FontType   `json:"fontType,omitempty"`
+	Highlight     *bool      `json:"highlight,omitempty"`
+	IsSubtle      *bool      `json:"isSubtle,omitempty"`
+	Italic        *bool      `json:"italic,omitempty"`
+	SelectAction  Node       `json:"selectAction,omitempty"`
+	Size          TextSize   `json:"size,omitempty"`
+	Strikethrough *bool      `json:"strikethrough,omitempty"`
+	Underline     *bool      `json:"underline,omitempty"`
+	Weight        FontWeight `json:"weight,omitempty"`
+}
+
+// NodeType returns TextRun element type
+func (t *TextRun) NodeType() string {
+	return TextRunType
+}
+
+// Prepare sets TextRun type and validates its fields
+func (t *TextRun) Prepare() error {
 	t.Type = TextRunType
+	return check(t)
+}
+
+func (t *TextRun) check(r *reporter) {
 	if t.Text == "" {
-		return errors.New("TextRun must have text")
+		r.required("TextRun must have text")
 	}
-	return nil
+	r.enum("color", t.Color)
+	r.enum("fontType", t.FontType)
+	r.enum("size", t.Size)
+	r.enum("weight", t.Weight)
 }
@@ -0,0 +1,339 @@
+package cards
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Enum values are compared case-insensitively like clients do. Empty value means default.
+
+// TextSize controls size of text
+type TextSize string
+
+// TextSize values
+const (
+	SizeDefault    TextSize = "default"
+	SizeSmall      TextSize = "small"
+	SizeMedium     TextSize = "medium"
+	SizeLarge      TextSize = "large"
+	SizeExtraLarge TextSize = "extraLarge"
+)
+
+// IsValid tells if the value is known
+func (v TextSize) IsValid() bool {
+	return isEnumValue(string(v),
+		string(SizeDefault), string(SizeSmall), string(SizeMedium), string(SizeLarge), string(SizeExtraLarge))
+}
+
+// FontWeight controls weight of text
+type FontWeight string
+
+// FontWeight values
+const (
+	WeightDefault FontWeight = "default"
+	WeightLighter FontWeight = "lighter"
+	WeightBolder  FontWeight = "bolder"
+)
+
+// IsValid tells if the value is known
+func (v FontWeight) IsValid() bool {
+	return isEnumValue(string(v), string(WeightDefault), string(WeightLighter), string(WeightBolder))
+}
+
+// Color controls color of text
+type Color string
+
+// Color values
+const (
+	ColorDefault   Color = "default"
+	ColorDark      Color = "dark"
+	ColorLight     Color = "light"
+	ColorAccent    Color = "accent"
+	ColorGood      Color = "good"
+	ColorWarning   Color = "warning"
+	ColorAttention Color = "attention"
+)
+
+// IsValid tells if the value is known
+func (v Color) IsValid() bool {
+	return isEnumValue(string(v),
+		string(ColorDefault), string(ColorDark), string(ColorLight), string(ColorAccent),
+		string(ColorGood), string(ColorWarning), string(ColorAttention))
+}
+
+// FontType controls type of font
+type FontType string
+
+// FontType values
+const (
+	FontTypeDefault   FontType = "default"
+	FontTypeMonospace FontType = "monospace"
+)
+
+// IsValid tells if the value is known
+func (v FontType) IsValid() bool {
+	return isEnumValue(string(v), string(FontTypeDefault), string(FontTypeMonospace))
+}
+
+// Spacing controls amount of space between this element and the preceding element
+type Spacing string
+
+// Spacing values
+const (
+	SpacingDefault    Spacing = "default"
+	SpacingNone       Spacing = "none"
+	SpacingSmall      Spacing = "small"
+	SpacingMedium     Spacing = "medium"
+	SpacingLarge      Spacing = "large"
+	SpacingExtraLarge Spacing = "extraLarge"
+	SpacingPadding    Spacing = "padding"
+)
+
+// IsValid tells if the value is known
+func (v Spacing) IsValid() bool {
+	return isEnumValue(string(v),
+		string(SpacingDefault), string(SpacingNone), string(SpacingSmall), string(SpacingMedium),
+		string(SpacingLarge), string(SpacingExtraLarge), string(SpacingPadding))
+}
+
+// HorizontalAlignment controls how content is horizontally positioned
+type HorizontalAlignment string
+
+// HorizontalAlignment values
+const (
+	HorizontalAlignmentLeft   HorizontalAlignment = "left"
+	HorizontalAlignmentCenter HorizontalAlignment = "center"
+	HorizontalAlignmentRight  HorizontalAlignment = "right"
+)
+
+// IsValid tells if the value is known
+func (v HorizontalAlignment) IsValid() bool {
+	return isEnumValue(string(v),
+		string(HorizontalAlignmentLeft), string(HorizontalAlignmentCenter), string(HorizontalAlignmentRight))
+}
+
+// VerticalAlignment controls how content is vertically positioned
+type VerticalAlignment string
+
+// VerticalAlignment values
+const (
+	VerticalAlignmentTop    VerticalAlignment = "top"
+	VerticalAlignmentCenter VerticalAlignment = "center"
+	VerticalAlignmentBottom VerticalAlignment = "bottom"
+)
+
+// IsValid tells if the value is known
+func (v VerticalAlignment) IsValid() bool {
+	return isEnumValue(string(v),
+		string(VerticalAlignmentTop), string(VerticalAlignmentCenter), string(VerticalAlignmentBottom))
+}
+
+// ContainerStyle is style hint for Container, ColumnSet and Column
+type ContainerStyle string
+
+// ContainerStyle values
+const (
+	ContainerStyleDefault   ContainerStyle = "default"
+	ContainerStyleEmphasis  ContainerStyle = "emphasis"
+	ContainerStyleGood      ContainerStyle = "good"
+	ContainerStyleAttention ContainerStyle = "attention"
+	ContainerStyleWarning   ContainerStyle = "warning"
+	ContainerStyleAccent    ContainerStyle = "accent"
+)
+
+// IsValid tells if the value is known
+func (v ContainerStyle) IsValid() bool {
+	return isEnumValue(string(v),
+		string(ContainerStyleDefault), string(ContainerStyleEmphasis), string(ContainerStyleGood),
+		string(ContainerStyleAttention), string(ContainerStyleWarning), string(ContainerStyleAccent))
+}
+
+// ActionStyle controls style of an action
+type ActionStyle string
+
+// ActionStyle values
+const (
+	ActionStyleDefault     ActionStyle = "default"
+	ActionStylePositive    ActionStyle = "positive"
+	ActionStyleDestructive ActionStyle = "destructive"
+)
+
+// IsValid tells if the value is known
+func (v ActionStyle) IsValid() bool {
+	return isEnumValue(string(v), string(ActionStyleDefault), string(ActionStylePositive), string(ActionStyleDestructive))
+}
+
+// ImageStyle controls how Image is displayed
+type ImageStyle string
+
+// ImageStyle values
+const (
+	ImageStyleDefault ImageStyle = "default"
+	ImageStylePerson  ImageStyle = "person"
+)
+
+// IsValid tells if the value is known
+func (v ImageStyle) IsValid() bool {
+	return isEnumValue(string(v), string(ImageStyleDefault), string(ImageStylePerson))
+}
+
+// ImageSize controls approximate size of the image
+type ImageSize string
+
+// ImageSize values
+const (
+	ImageSizeAuto    ImageSize = "auto"
+	ImageSizeStretch ImageSize = "stretch"
+	ImageSizeSmall   ImageSize = "small"
+	ImageSizeMedium  ImageSize = "medium"
+	ImageSizeLarge   ImageSize = "large"
+)
+
+// IsValid tells if the value is known
+func (v ImageSize) IsValid() bool {
+	return isEnumValue(string(v),
+		string(ImageSizeAuto), string(ImageSizeStretch), string(ImageSizeSmall), string(ImageSizeMedium), string(ImageSizeLarge))
+}
+
+// FillMode describes how the background image should fill the area
+type FillMode string
+
+// FillMode values
+const (
+	FillModeCover              FillMode = "cover"
+	FillModeRepeatHorizontally FillMode = "repeatHorizontally"
+	FillModeRepeatVertically   FillMode = "repeatVertically"
+	FillModeRepeat             FillMode = "repeat"
+)
+
+// IsValid tells if the value is known
+func (v FillMode) IsValid() bool {
+	return isEnumValue(string(v),
+		string(FillModeCover), string(FillModeRepeatHorizontally), string(FillModeRepeatVertically), string(FillModeRepeat))
+}
+
+// BlockElementHeight specifies the height of the element.
+// Image also accepts pixel values, e.g. "50px".
+type BlockElementHeight string
+
+// BlockElementHeight values
+const (
+	HeightAuto    BlockElementHeight = "auto"
+	HeightStretch BlockElementHeight = "stretch"
+)
+
+// IsValid tells if the value is known
+func (v BlockElementHeight) IsValid() bool {
+	return isEnumValue(string(v), string(HeightAuto), string(HeightStretch))
+}
+
+// ColumnWidth is "auto", "stretch", a relative weight (e.g. "2") or pixel width (e.g. "50px")
+type ColumnWidth string
+
+// ColumnWidth values
+const (
+	ColumnWidthAuto    ColumnWidth = "auto"
+	ColumnWidthStretch ColumnWidth = "stretch"
+)
+
+// IsValid tells if the value is a keyword, a positive weight or a pixel width
+func (v ColumnWidth) IsValid() bool {
+	if isEnumValue(string(v), string(ColumnWidthAuto), string(ColumnWidthStretch)) {
+		return true
+	}
+	if w, err := strconv.ParseFloat(string(v), 64); err == nil {
+		return w > 0
+	}
+	_, err := ParsePixels(string(v))
+	return err == nil
+}
+
+// TextInputStyle is style hint for Input.Text
+type TextInputStyle string
+
+// TextInputStyle values
+const (
+	TextInputStyleText     TextInputStyle = "text"
+	TextInputStyleTel      TextInputStyle = "tel"
+	TextInputStyleURL      TextInputStyle = "url"
+	TextInputStyleEmail    TextInputStyle = "email"
+	TextInputStylePassword TextInputStyle = "password"
+)
+
+// IsValid tells if the value is known
+func (v TextInputStyle) IsValid() bool {
+	return isEnumValue(string(v),
+		string(TextInputStyleText), string(TextInputStyleTel), string(TextInputStyleURL),
+		string(TextInputStyleEmail), string(TextInputStylePassword))
+}
+
+// ChoiceInputStyle is style hint for Input.ChoiceSet
+type ChoiceInputStyle string
+
+// ChoiceInputStyle values
+const (
+	ChoiceInputStyleCompact  ChoiceInputStyle = "compact"
+	ChoiceInputStyleExpanded ChoiceInputStyle = "expanded"
+	ChoiceInputStyleFiltered ChoiceInputStyle = "filtered"
+)
+
+// IsValid tells if the value is known
+func (v ChoiceInputStyle) IsValid() bool {
+	return isEnumValue(string(v),
+		string(ChoiceInputStyleCompact), string(ChoiceInputStyleExpanded), string(ChoiceInputStyleFiltered))
+}
+
+// ParsePixels parses pixel value like "50px" and returns number of pixels
+func ParsePixels(v string) (int, error) {
+	s := strings.TrimSpace(v)
+	if !strings.HasSuffix(strings.ToLower(s), "px") {
+		return 0, fmt.Errorf("%q is not a pixel value", v)
+	}
+	px, err := strconv.Atoi(strings.TrimSpace(s[:len(s)-2]))
+	if err != nil || px < 0 {
+		return 0, fmt.Errorf("%q is not a pixel value", v)
+	}
+	return px, nil
+}
+
+// isEnumValue tells if v is empty or equal to one of allowed values ignoring case
+func isEnumValue(v string, allowed ...string) bool {
+	if v == "" {
+		return true
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(v, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// enumValue is a constrained string property
+type enumValue interface {
+	IsValid() bool
+}
+
+// enum reports invalid enum value of the field
+func (r *reporter) enum(field string, v enumValue) {
+	if !v.IsValid() {
+		r.add(CodeInvalidValue, fmt.Sprintf("%s has invalid value %q", field, v))
+	}
+}
+
+// pixels reports field which has to be a pixel value
+func (r *reporter) pixels(field string, v string) {
+	if v == "" {
+		return
+	}
+	if _, err := ParsePixels(v); err != nil {
+		r.add(CodeInvalidValue, fmt.Sprintf("%s has invalid value %q, expected pixels like \"50px\"", field, v))
+	}
+}
+
+// layout reports invalid values of common element layout fields
+func (r *reporter) layout(height BlockElementHeight, spacing Spacing) {
+	r.enum("height", height)
+	r.enum("spacing", spacing)
+}
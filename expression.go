@@ -0,0 +1,933 @@
+package cards
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Expression is a parsed Adaptive Expression (https://docs.microsoft.com/en-us/azure/bot-service/adaptive-expressions/adaptive-expressions-prebuilt-functions).
+// It supports property paths, indexers, arithmetic, comparison and logical operators,
+// string interpolation in backticks and function calls.
+// Expression is immutable and safe for concurrent use.
+type Expression struct {
+	src  string
+	root exprNode
+}
+
+// ParseExpression parses expression source, e.g. "count(items) > 0"
+func ParseExpression(src string) (*Expression, error) {
+	p := &exprParser{lex: newExprLexer(src)}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at %d in expression %q", p.tok.text, p.tok.pos, src)
+	}
+	return &Expression{src: src, root: root}, nil
+}
+
+// MustParseExpression is like ParseExpression but panics on error
+func MustParseExpression(src string) *Expression {
+	e, err := ParseExpression(src)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// String returns expression source
+func (e *Expression) String() string {
+	return e.src
+}
+
+// Evaluate evaluates expression with data as memory, properties of data are available as identifiers.
+// Data can be any value serializable to JSON, numbers are evaluated as float64.
+// Missing properties evaluate to nil.
+func (e *Expression) Evaluate(data interface{}) (interface{}, error) {
+	normalized, err := normalizeData(data)
+	if err != nil {
+		return nil, err
+	}
+	return e.eval(dataScope{data: normalized})
+}
+
+// EvaluateBool evaluates expression as condition: only nil and false are false
+func (e *Expression) EvaluateBool(data interface{}) (bool, error) {
+	v, err := e.Evaluate(data)
+	if err != nil {
+		return false, err
+	}
+	return isTruthy(v), nil
+}
+
+func (e *Expression) eval(s exprScope) (interface{}, error) {
+	v, err := e.root.eval(s)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", e.src, err)
+	}
+	return v, nil
+}
+
+// Evaluate parses and evaluates expression with provided data
+func Evaluate(expr string, data interface{}) (interface{}, error) {
+	e, err := ParseExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	return e.Evaluate(data)
+}
+
+// exprScope resolves top level identifiers
+type exprScope interface {
+	lookup(name string) (interface{}, bool)
+}
+
+// dataScope resolves identifiers as properties of data
+type dataScope struct {
+	data interface{}
+}
+
+func (s dataScope) lookup(name string) (interface{}, bool) {
+	return property(s.data, name)
+}
+
+// Lexer
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokTemplate
+	tokIdent
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+type exprLexer struct {
+	src string
+	pos int
+}
+
+func newExprLexer(src string) *exprLexer {
+	return &exprLexer{src: src}
+}
+
+var exprOperators = []string{
+	"==", "!=", "<>", "<=", ">=", "&&", "||",
+	"(", ")", "[", "]", "{", "}", ",", ".", ":",
+	"+", "-", "*", "/", "%", "^", "&", "!", "<", ">",
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || c == '@' || c == '#' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+func (l *exprLexer) next() (token, error) {
+	for l.pos < len(l.src) && strings.IndexByte(" \t\r\n", l.src[l.pos]) >= 0 {
+		l.pos++
+	}
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+	start := l.pos
+	c := l.src[l.pos]
+	switch {
+	case c >= '0' && c <= '9':
+		for l.pos < len(l.src) && (l.src[l.pos] >= '0' && l.src[l.pos] <= '9' || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		text := l.src[start:l.pos]
+		num, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return token{}, fmt.Errorf("invalid number %q at %d", text, start)
+		}
+		return token{kind: tokNumber, text: text, num: num, pos: start}, nil
+	case c == '\'' || c == '"':
+		text, err := l.readQuoted(c)
+		if err != nil {
+			return token{}, err
+		}
+		return token{kind: tokString, text: text, pos: start}, nil
+	case c == '`':
+		text, err := l.readQuoted(c)
+		if err != nil {
+			return token{}, err
+		}
+		return token{kind: tokTemplate, text: text, pos: start}, nil
+	case isIdentStart(c):
+		l.pos++
+		for l.pos < len(l.src) && isIdentPart(l.src[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokIdent, text: l.src[start:l.pos], pos: start}, nil
+	}
+	for _, op := range exprOperators {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokPunct, text: op, pos: start}, nil
+		}
+	}
+	return token{}, fmt.Errorf("unexpected character %q at %d", c, start)
+}
+
+// readQuoted reads string literal handling backslash escapes.
+// Template strings keep ${...} parts untouched for later parsing.
+func (l *exprLexer) readQuoted(quote byte) (string, error) {
+	start := l.pos
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == quote:
+			l.pos++
+			return b.String(), nil
+		case c == '\\' && l.pos+1 < len(l.src):
+			l.pos++
+			switch e := l.src[l.pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+		l.pos++
+	}
+	return "", fmt.Errorf("unterminated string at %d", start)
+}
+
+// Parser
+
+type exprParser struct {
+	lex *exprLexer
+	tok token
+}
+
+func (p *exprParser) next() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *exprParser) is(punct string) bool {
+	return p.tok.kind == tokPunct && p.tok.text == punct
+}
+
+func (p *exprParser) expect(punct string) error {
+	if !p.is(punct) {
+		if p.tok.kind == tokEOF {
+			return fmt.Errorf("expected %q but expression ended", punct)
+		}
+		return fmt.Errorf("expected %q at %d but got %q", punct, p.tok.pos, p.tok.text)
+	}
+	return p.next()
+}
+
+// binary operators by precedence from lowest to highest
+var exprPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<>"},
+	{"<", "<=", ">", ">="},
+	{"+", "-", "&"},
+	{"*", "/", "%"},
+}
+
+func (p *exprParser) parseExpr() (exprNode, error) {
+	return p.parseBinary(0)
+}
+
+func (p *exprParser) parseBinary(level int) (exprNode, error) {
+	if level == len(exprPrecedence) {
+		return p.parsePower()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, candidate := range exprPrecedence[level] {
+			if p.is(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return left, nil
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+// parsePower parses right associative ^ operator
+func (p *exprParser) parsePower() (exprNode, error) {
+	base, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.is("^") {
+		return base, nil
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	exp, err := p.parsePower()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: "^", left: base, right: exp}, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.is("!") || p.is("-") || p.is("+") {
+		op := p.tok.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, x: x}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (exprNode, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.is("."):
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokIdent {
+				return nil, fmt.Errorf("expected property name at %d", p.tok.pos)
+			}
+			x = &memberNode{x: x, name: p.tok.text}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		case p.is("["):
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			idx, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &indexNode{x: x, index: idx}
+		default:
+			return x, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokNumber:
+		return &literalNode{value: tok.num}, p.next()
+	case tokString:
+		return &literalNode{value: tok.text}, p.next()
+	case tokTemplate:
+		n, err := parseInterpolation(tok.text)
+		if err != nil {
+			return nil, err
+		}
+		return n, p.next()
+	case tokIdent:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+		if p.is("(") {
+			return p.parseCall(tok.text)
+		}
+		return &identNode{name: tok.text}, nil
+	case tokPunct:
+		switch tok.text {
+		case "(":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "[":
+			return p.parseArray()
+		case "{":
+			return p.parseObject()
+		}
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos)
+}
+
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	args, err := p.parseList(")")
+	if err != nil {
+		return nil, err
+	}
+	return &callNode{name: name, args: args}, nil
+}
+
+func (p *exprParser) parseArray() (exprNode, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	items, err := p.parseList("]")
+	if err != nil {
+		return nil, err
+	}
+	return &arrayNode{items: items}, nil
+}
+
+// parseList parses comma separated expressions up to closing punct
+func (p *exprParser) parseList(closing string) ([]exprNode, error) {
+	var items []exprNode
+	for !p.is(closing) {
+		item, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if !p.is(",") {
+			break
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	return items, p.expect(closing)
+}
+
+func (p *exprParser) parseObject() (exprNode, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	obj := &objectNode{}
+	for !p.is("}") {
+		if p.tok.kind != tokIdent && p.tok.kind != tokString {
+			return nil, fmt.Errorf("expected object key at %d", p.tok.pos)
+		}
+		obj.keys = append(obj.keys, p.tok.text)
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		v, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		obj.values = append(obj.values, v)
+		if !p.is(",") {
+			break
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	return obj, p.expect("}")
+}
+
+// parseInterpolation parses content of `...${expr}...` string
+func parseInterpolation(text string) (exprNode, error) {
+	bindings, err := findBindings(text)
+	if err != nil {
+		return nil, err
+	}
+	n := &interpolationNode{}
+	last := 0
+	for _, b := range bindings {
+		if b.start > last {
+			n.parts = append(n.parts, &literalNode{value: text[last:b.start]})
+		}
+		e, err := ParseExpression(b.expr)
+		if err != nil {
+			return nil, err
+		}
+		n.parts = append(n.parts, e.root)
+		last = b.end
+	}
+	if last < len(text) {
+		n.parts = append(n.parts, &literalNode{value: text[last:]})
+	}
+	return n, nil
+}
+
+// AST
+
+type exprNode interface {
+	eval(s exprScope) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(s exprScope) (interface{}, error) {
+	return n.value, nil
+}
+
+type identNode struct {
+	name string
+}
+
+func (n *identNode) eval(s exprScope) (interface{}, error) {
+	v, _ := s.lookup(n.name)
+	return v, nil
+}
+
+type memberNode struct {
+	x    exprNode
+	name string
+}
+
+func (n *memberNode) eval(s exprScope) (interface{}, error) {
+	x, err := n.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	v, _ := property(x, n.name)
+	return v, nil
+}
+
+type indexNode struct {
+	x     exprNode
+	index exprNode
+}
+
+func (n *indexNode) eval(s exprScope) (interface{}, error) {
+	x, err := n.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := n.index.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	switch i := idx.(type) {
+	case float64:
+		if arr, ok := x.([]interface{}); ok {
+			if math.IsNaN(i) || math.IsInf(i, 0) || i != math.Trunc(i) {
+				return nil, fmt.Errorf("invalid index %v", stringify(i))
+			}
+			// compared as float, huge indexes overflow int
+			if i < 0 || i >= float64(len(arr)) {
+				return nil, nil
+			}
+			return arr[int(i)], nil
+		}
+		v, _ := property(x, stringify(i))
+		return v, nil
+	case string:
+		v, _ := property(x, i)
+		return v, nil
+	}
+	return nil, fmt.Errorf("invalid index %v", idx)
+}
+
+type arrayNode struct {
+	items []exprNode
+}
+
+func (n *arrayNode) eval(s exprScope) (interface{}, error) {
+	res := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res, nil
+}
+
+type objectNode struct {
+	keys   []string
+	values []exprNode
+}
+
+func (n *objectNode) eval(s exprScope) (interface{}, error) {
+	res := make(map[string]interface{}, len(n.keys))
+	for i, k := range n.keys {
+		v, err := n.values[i].eval(s)
+		if err != nil {
+			return nil, err
+		}
+		res[k] = v
+	}
+	return res, nil
+}
+
+type interpolationNode struct {
+	parts []exprNode
+}
+
+func (n *interpolationNode) eval(s exprScope) (interface{}, error) {
+	var b strings.Builder
+	for _, part := range n.parts {
+		v, err := part.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(stringify(v))
+	}
+	return b.String(), nil
+}
+
+type unaryNode struct {
+	op string
+	x  exprNode
+}
+
+func (n *unaryNode) eval(s exprScope) (interface{}, error) {
+	x, err := n.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !isTruthy(x), nil
+	}
+	num, err := toNumber(x)
+	if err != nil {
+		return nil, fmt.Errorf("operator %s: %w", n.op, err)
+	}
+	if n.op == "-" {
+		return -num, nil
+	}
+	return num, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *binaryNode) eval(s exprScope) (interface{}, error) {
+	left, err := n.left.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	// short circuit logical operators
+	switch n.op {
+	case "&&":
+		if !isTruthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		return isTruthy(right), nil
+	case "||":
+		if isTruthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		return isTruthy(right), nil
+	}
+	right, err := n.right.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	res, err := applyBinary(n.op, left, right)
+	if err != nil {
+		return nil, fmt.Errorf("operator %s: %w", n.op, err)
+	}
+	return res, nil
+}
+
+func applyBinary(op string, left, right interface{}) (interface{}, error) {
+	switch op {
+	case "==":
+		return valuesEqual(left, right), nil
+	case "!=", "<>":
+		return !valuesEqual(left, right), nil
+	case "&":
+		return stringify(left) + stringify(right), nil
+	case "+":
+		_, ls := left.(string)
+		_, rs := right.(string)
+		if ls || rs {
+			return stringify(left) + stringify(right), nil
+		}
+	case "<", "<=", ">", ">=":
+		cmp, err := compareValues(left, right)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}
+	l, err := toNumber(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := toNumber(right)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	case "%":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(l, r), nil
+	case "^":
+		return math.Pow(l, r), nil
+	}
+	return nil, fmt.Errorf("unknown operator")
+}
+
+type callNode struct {
+	name string
+	args []exprNode
+}
+
+func (n *callNode) eval(s exprScope) (interface{}, error) {
+	// if evaluates only the selected branch
+	if n.name == "if" {
+		if len(n.args) != 3 {
+			return nil, fmt.Errorf("if: expects 3 arguments, got %d", len(n.args))
+		}
+		cond, err := n.args[0].eval(s)
+		if err != nil {
+			return nil, err
+		}
+		if isTruthy(cond) {
+			return n.args[1].eval(s)
+		}
+		return n.args[2].eval(s)
+	}
+	fn, ok := lookupFunction(n.name)
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", n.name)
+	}
+	args := make([]interface{}, 0, len(n.args))
+	for _, a := range n.args {
+		v, err := a.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	res, err := fn(args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return res, nil
+}
+
+// Values
+
+// normalizeData converts any value to generic JSON representation
+// (maps, slices, strings, float64, bool and nil)
+func normalizeData(data interface{}) (interface{}, error) {
+	switch d := data.(type) {
+	case nil, string, bool, float64:
+		return d, nil
+	}
+	var raw []byte
+	switch d := data.(type) {
+	case []byte:
+		raw = d
+	case json.RawMessage:
+		raw = d
+	default:
+		var err error
+		raw, err = json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// property returns object property, ok is false if it's missing
+func property(v interface{}, name string) (interface{}, bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		res, ok := t[name]
+		return res, ok
+	case []interface{}:
+		if name == "length" {
+			return float64(len(t)), true
+		}
+	case string:
+		if name == "length" {
+			// characters like length() counts
+			return float64(utf8.RuneCountInString(t)), true
+		}
+	}
+	return nil, false
+}
+
+// isTruthy tells if value is considered true in conditions.
+// Like in Adaptive Expressions only null and false are false.
+func isTruthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	default:
+		return true
+	}
+}
+
+// stringify formats value as string
+func stringify(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	default:
+		s, err := marshalCompact(t)
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return s
+	}
+}
+
+func toNumber(v interface{}) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case nil:
+		return 0, fmt.Errorf("null is not a number")
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", t)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("%v is not a number", v)
+}
+
+func valuesEqual(a, b interface{}) bool {
+	if an, ok := a.(float64); ok {
+		if bn, ok := b.(float64); ok {
+			return an == bn
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareValues compares numbers or strings
+func compareValues(a, b interface{}) (int, error) {
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			return strings.Compare(as, bs), nil
+		}
+	}
+	an, err := toNumber(a)
+	if err != nil {
+		return 0, err
+	}
+	bn, err := toNumber(b)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case an < bn:
+		return -1, nil
+	case an > bn:
+		return 1, nil
+	}
+	return 0, nil
+}
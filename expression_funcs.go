@@ -0,0 +1,702 @@
+package cards
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExpressionFunc is a function available in expressions.
+// Arguments are evaluated values: nil, bool, float64, string, []interface{} or map[string]interface{}.
+type ExpressionFunc func(args ...interface{}) (interface{}, error)
+
+var (
+	functionsMu     sync.RWMutex
+	customFunctions = map[string]ExpressionFunc{}
+)
+
+// RegisterFunction makes custom function available in expressions and templates.
+// RegisterFunction panics if name is empty, fn is nil or the function already exists.
+func RegisterFunction(name string, fn ExpressionFunc) {
+	if name == "" {
+		panic("cards: RegisterFunction name is empty")
+	}
+	if fn == nil {
+		panic("cards: RegisterFunction function is nil for " + name)
+	}
+	functionsMu.Lock()
+	defer functionsMu.Unlock()
+	_, builtin := builtinFunctions[name]
+	if _, dup := customFunctions[name]; dup || builtin || name == "if" {
+		panic("cards: RegisterFunction called twice for " + name)
+	}
+	customFunctions[name] = fn
+}
+
+func lookupFunction(name string) (ExpressionFunc, bool) {
+	if fn, ok := builtinFunctions[name]; ok {
+		return fn, true
+	}
+	functionsMu.RLock()
+	defer functionsMu.RUnlock()
+	fn, ok := customFunctions[name]
+	return fn, ok
+}
+
+// builtinFunctions is a subset of Adaptive Expressions prebuilt functions.
+// "if" is handled by the evaluator because only one branch is evaluated.
+var builtinFunctions map[string]ExpressionFunc
+
+func init() {
+	builtinFunctions = map[string]ExpressionFunc{
+		// logical and comparison
+		"not":    arity(1, func(a []interface{}) (interface{}, error) { return !isTruthy(a[0]), nil }),
+		"and":    fnAnd,
+		"or":     fnOr,
+		"equals": arity(2, func(a []interface{}) (interface{}, error) { return valuesEqual(a[0], a[1]), nil }),
+		"exists": arity(1, func(a []interface{}) (interface{}, error) { return a[0] != nil, nil }),
+		"empty":  arity(1, fnEmpty),
+		// strings
+		"concat":    fnConcat,
+		"length":    arity(1, fnLength),
+		"toUpper":   stringFn(strings.ToUpper),
+		"toLower":   stringFn(strings.ToLower),
+		"trim":      stringFn(strings.TrimSpace),
+		"substring": fnSubstring,
+		"replace":   arity(3, fnReplace),
+		"split":     arity(2, fnSplit),
+		"startsWith": arity(2, func(a []interface{}) (interface{}, error) {
+			return strings.HasPrefix(stringify(a[0]), stringify(a[1])), nil
+		}),
+		"endsWith": arity(2, func(a []interface{}) (interface{}, error) {
+			return strings.HasSuffix(stringify(a[0]), stringify(a[1])), nil
+		}),
+		"indexOf": arity(2, fnIndexOf),
+		// collections
+		"count":       arity(1, fnCount),
+		"contains":    arity(2, fnContains),
+		"first":       arity(1, fnFirst),
+		"last":        arity(1, fnLast),
+		"join":        fnJoin,
+		"createArray": func(args ...interface{}) (interface{}, error) { return append([]interface{}{}, args...), nil },
+		"coalesce":    fnCoalesce,
+		// math
+		"add":     numbersFn(2, func(n []float64) (interface{}, error) { return n[0] + n[1], nil }),
+		"sub":     numbersFn(2, func(n []float64) (interface{}, error) { return n[0] - n[1], nil }),
+		"mul":     numbersFn(2, func(n []float64) (interface{}, error) { return n[0] * n[1], nil }),
+		"div":     numbersFn(2, fnDiv),
+		"mod":     numbersFn(2, fnMod),
+		"max":     fnMax,
+		"min":     fnMin,
+		"sum":     arity(1, fnSum),
+		"average": arity(1, fnAverage),
+		"round":   fnRound,
+		"floor":   numbersFn(1, func(n []float64) (interface{}, error) { return math.Floor(n[0]), nil }),
+		"ceiling": numbersFn(1, func(n []float64) (interface{}, error) { return math.Ceil(n[0]), nil }),
+		// conversion
+		"string": arity(1, func(a []interface{}) (interface{}, error) { return stringify(a[0]), nil }),
+		"int":    arity(1, fnInt),
+		"float":  arity(1, func(a []interface{}) (interface{}, error) { return toNumber(a[0]) }),
+		"bool":   arity(1, func(a []interface{}) (interface{}, error) { return isTruthy(a[0]), nil }),
+		"json":   arity(1, fnJSON),
+		// formatting and dates
+		"formatNumber":   fnFormatNumber,
+		"formatDateTime": fnFormatDateTime,
+		"utcNow":         fnUtcNow,
+		"addDays":        addDurationFn(24 * time.Hour),
+		"addHours":       addDurationFn(time.Hour),
+		"addMinutes":     addDurationFn(time.Minute),
+		"year":           datePartFn(func(t time.Time) int { return t.Year() }),
+		"month":          datePartFn(func(t time.Time) int { return int(t.Month()) }),
+		"dayOfMonth":     datePartFn(func(t time.Time) int { return t.Day() }),
+		"dayOfWeek":      datePartFn(func(t time.Time) int { return int(t.Weekday()) }),
+	}
+}
+
+// Helpers building functions with checked arguments
+
+func arity(n int, fn func(args []interface{}) (interface{}, error)) ExpressionFunc {
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) != n {
+			return nil, fmt.Errorf("expects %d arguments, got %d", n, len(args))
+		}
+		return fn(args)
+	}
+}
+
+func stringFn(fn func(string) string) ExpressionFunc {
+	return arity(1, func(a []interface{}) (interface{}, error) {
+		return fn(stringify(a[0])), nil
+	})
+}
+
+func numbersFn(n int, fn func(nums []float64) (interface{}, error)) ExpressionFunc {
+	return arity(n, func(a []interface{}) (interface{}, error) {
+		nums, err := toNumbers(a)
+		if err != nil {
+			return nil, err
+		}
+		return fn(nums)
+	})
+}
+
+func toNumbers(args []interface{}) ([]float64, error) {
+	nums := make([]float64, 0, len(args))
+	for _, a := range args {
+		n, err := toNumber(a)
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, n)
+	}
+	return nums, nil
+}
+
+// Logical
+
+func fnAnd(args ...interface{}) (interface{}, error) {
+	for _, a := range args {
+		if !isTruthy(a) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func fnOr(args ...interface{}) (interface{}, error) {
+	for _, a := range args {
+		if isTruthy(a) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func fnEmpty(a []interface{}) (interface{}, error) {
+	switch t := a[0].(type) {
+	case nil:
+		return true, nil
+	case string:
+		return t == "", nil
+	case []interface{}:
+		return len(t) == 0, nil
+	case map[string]interface{}:
+		return len(t) == 0, nil
+	}
+	return false, nil
+}
+
+// Strings
+
+func fnConcat(args ...interface{}) (interface{}, error) {
+	allArrays := len(args) > 0
+	for _, a := range args {
+		if _, ok := a.([]interface{}); !ok {
+			allArrays = false
+		}
+	}
+	if allArrays {
+		var res []interface{}
+		for _, a := range args {
+			res = append(res, a.([]interface{})...)
+		}
+		return res, nil
+	}
+	var b strings.Builder
+	for _, a := range args {
+		b.WriteString(stringify(a))
+	}
+	return b.String(), nil
+}
+
+func fnLength(a []interface{}) (interface{}, error) {
+	return float64(len([]rune(stringify(a[0])))), nil
+}
+
+func fnSubstring(args ...interface{}) (interface{}, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, fmt.Errorf("expects 2 or 3 arguments, got %d", len(args))
+	}
+	s := []rune(stringify(args[0]))
+	start, err := toInt(args[1])
+	if err != nil {
+		return nil, err
+	}
+	end := len(s)
+	if len(args) == 3 {
+		length, err := toInt(args[2])
+		if err != nil {
+			return nil, err
+		}
+		end = start + length
+	}
+	if start < 0 || start > len(s) || end < start || end > len(s) {
+		return nil, fmt.Errorf("index out of range")
+	}
+	return string(s[start:end]), nil
+}
+
+func fnReplace(a []interface{}) (interface{}, error) {
+	return strings.Replace(stringify(a[0]), stringify(a[1]), stringify(a[2]), -1), nil
+}
+
+func fnSplit(a []interface{}) (interface{}, error) {
+	parts := strings.Split(stringify(a[0]), stringify(a[1]))
+	res := make([]interface{}, 0, len(parts))
+	for _, p := range parts {
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+func fnIndexOf(a []interface{}) (interface{}, error) {
+	if arr, ok := a[0].([]interface{}); ok {
+		for i, v := range arr {
+			if valuesEqual(v, a[1]) {
+				return float64(i), nil
+			}
+		}
+		return float64(-1), nil
+	}
+	s := stringify(a[0])
+	i := strings.Index(s, stringify(a[1]))
+	if i < 0 {
+		return float64(-1), nil
+	}
+	return float64(len([]rune(s[:i]))), nil
+}
+
+// Collections
+
+func fnCount(a []interface{}) (interface{}, error) {
+	switch t := a[0].(type) {
+	case []interface{}:
+		return float64(len(t)), nil
+	case map[string]interface{}:
+		return float64(len(t)), nil
+	case string:
+		return float64(len([]rune(t))), nil
+	case nil:
+		return float64(0), nil
+	}
+	return nil, fmt.Errorf("%v is not a collection", a[0])
+}
+
+func fnContains(a []interface{}) (interface{}, error) {
+	switch t := a[0].(type) {
+	case []interface{}:
+		for _, v := range t {
+			if valuesEqual(v, a[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		_, ok := t[stringify(a[1])]
+		return ok, nil
+	case string:
+		return strings.Contains(t, stringify(a[1])), nil
+	}
+	return false, nil
+}
+
+func fnFirst(a []interface{}) (interface{}, error) {
+	switch t := a[0].(type) {
+	case []interface{}:
+		if len(t) > 0 {
+			return t[0], nil
+		}
+	case string:
+		if r := []rune(t); len(r) > 0 {
+			return string(r[0]), nil
+		}
+	}
+	return nil, nil
+}
+
+func fnLast(a []interface{}) (interface{}, error) {
+	switch t := a[0].(type) {
+	case []interface{}:
+		if len(t) > 0 {
+			return t[len(t)-1], nil
+		}
+	case string:
+		if r := []rune(t); len(r) > 0 {
+			return string(r[len(r)-1]), nil
+		}
+	}
+	return nil, nil
+}
+
+// fnJoin joins array items with separator and optional last separator
+func fnJoin(args ...interface{}) (interface{}, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, fmt.Errorf("expects 2 or 3 arguments, got %d", len(args))
+	}
+	arr, ok := args[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%v is not an array", args[0])
+	}
+	items := make([]string, 0, len(arr))
+	for _, v := range arr {
+		items = append(items, stringify(v))
+	}
+	sep := stringify(args[1])
+	if len(args) == 3 && len(items) > 1 {
+		return strings.Join(items[:len(items)-1], sep) + stringify(args[2]) + items[len(items)-1], nil
+	}
+	return strings.Join(items, sep), nil
+}
+
+func fnCoalesce(args ...interface{}) (interface{}, error) {
+	for _, a := range args {
+		if a != nil {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
+// Math
+
+func fnDiv(n []float64) (interface{}, error) {
+	if n[1] == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	return n[0] / n[1], nil
+}
+
+func fnMod(n []float64) (interface{}, error) {
+	if n[1] == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	return math.Mod(n[0], n[1]), nil
+}
+
+// numbersOf flattens arguments which can be numbers or a single array of numbers
+func numbersOf(args []interface{}) ([]float64, error) {
+	if len(args) == 1 {
+		if arr, ok := args[0].([]interface{}); ok {
+			args = arr
+		}
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("expects at least one number")
+	}
+	return toNumbers(args)
+}
+
+func fnMax(args ...interface{}) (interface{}, error) {
+	nums, err := numbersOf(args)
+	if err != nil {
+		return nil, err
+	}
+	res := nums[0]
+	for _, n := range nums[1:] {
+		res = math.Max(res, n)
+	}
+	return res, nil
+}
+
+func fnMin(args ...interface{}) (interface{}, error) {
+	nums, err := numbersOf(args)
+	if err != nil {
+		return nil, err
+	}
+	res := nums[0]
+	for _, n := range nums[1:] {
+		res = math.Min(res, n)
+	}
+	return res, nil
+}
+
+func fnSum(a []interface{}) (interface{}, error) {
+	nums, err := numbersOf(a)
+	if err != nil {
+		return nil, err
+	}
+	var res float64
+	for _, n := range nums {
+		res += n
+	}
+	return res, nil
+}
+
+func fnAverage(a []interface{}) (interface{}, error) {
+	sum, err := fnSum(a)
+	if err != nil {
+		return nil, err
+	}
+	nums, _ := numbersOf(a)
+	return sum.(float64) / float64(len(nums)), nil
+}
+
+func fnRound(args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("expects 1 or 2 arguments, got %d", len(args))
+	}
+	nums, err := toNumbers(args)
+	if err != nil {
+		return nil, err
+	}
+	precision := 0.0
+	if len(nums) == 2 {
+		precision = nums[1]
+	}
+	p := math.Pow(10, precision)
+	return math.Round(nums[0]*p) / p, nil
+}
+
+// Conversion
+
+func toInt(v interface{}) (int, error) {
+	n, err := toNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	if n != math.Trunc(n) || math.Abs(n) > math.MaxInt32 {
+		return 0, fmt.Errorf("%v is not an integer", stringify(v))
+	}
+	return int(n), nil
+}
+
+func fnInt(a []interface{}) (interface{}, error) {
+	n, err := toNumber(a[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Trunc(n), nil
+}
+
+func fnJSON(a []interface{}) (interface{}, error) {
+	s, ok := a[0].(string)
+	if !ok {
+		return a[0], nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func marshalCompact(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// Formatting
+
+// maxPrecision limits digits after the point formatted by formatNumber
+const maxPrecision = 100
+
+// fnFormatNumber formats number with fixed precision and thousands separators:
+// formatNumber(1234.5, 2) is "1,234.50". Locale argument is accepted but only en-US is supported.
+func fnFormatNumber(args ...interface{}) (interface{}, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, fmt.Errorf("expects 2 or 3 arguments, got %d", len(args))
+	}
+	n, err := toNumber(args[0])
+	if err != nil {
+		return nil, err
+	}
+	precision, err := toInt(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if precision < 0 || precision > maxPrecision {
+		return nil, fmt.Errorf("precision must be from 0 to %d, got %d", maxPrecision, precision)
+	}
+	s := strconv.FormatFloat(math.Abs(n), 'f', precision, 64)
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i:]
+	}
+	var b strings.Builder
+	if n < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	b.WriteString(frac)
+	return b.String(), nil
+}
+
+// defaultDateTimeFormat is ISO 8601 format used by Adaptive Expressions
+const defaultDateTimeFormat = "yyyy-MM-ddTHH:mm:ss.fffZ"
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+func parseTimestamp(v interface{}) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%v is not a timestamp", v)
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a timestamp", s)
+}
+
+// fnFormatDateTime formats timestamp with .NET style custom format, e.g. "dd MMM yyyy"
+func fnFormatDateTime(args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, fmt.Errorf("expects 1 to 3 arguments, got %d", len(args))
+	}
+	t, err := parseTimestamp(args[0])
+	if err != nil {
+		return nil, err
+	}
+	format := defaultDateTimeFormat
+	if len(args) > 1 {
+		format = stringify(args[1])
+	}
+	return formatDotNetTime(t, format), nil
+}
+
+func fnUtcNow(args ...interface{}) (interface{}, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("expects at most 1 argument, got %d", len(args))
+	}
+	format := defaultDateTimeFormat
+	if len(args) == 1 {
+		format = stringify(args[0])
+	}
+	return formatDotNetTime(time.Now().UTC(), format), nil
+}
+
+func addDurationFn(unit time.Duration) ExpressionFunc {
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) < 2 || len(args) > 3 {
+			return nil, fmt.Errorf("expects 2 or 3 arguments, got %d", len(args))
+		}
+		t, err := parseTimestamp(args[0])
+		if err != nil {
+			return nil, err
+		}
+		n, err := toNumber(args[1])
+		if err != nil {
+			return nil, err
+		}
+		format := defaultDateTimeFormat
+		if len(args) == 3 {
+			format = stringify(args[2])
+		}
+		return formatDotNetTime(t.Add(time.Duration(n*float64(unit))), format), nil
+	}
+}
+
+func datePartFn(part func(time.Time) int) ExpressionFunc {
+	return arity(1, func(a []interface{}) (interface{}, error) {
+		t, err := parseTimestamp(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return float64(part(t)), nil
+	})
+}
+
+// dotNetTimeTokens maps .NET custom date format specifiers to formatters, longest first
+var dotNetTimeTokens = []struct {
+	token  string
+	format func(t time.Time) string
+}{
+	{"yyyy", func(t time.Time) string { return fmt.Sprintf("%04d", t.Year()) }},
+	{"yy", func(t time.Time) string { return fmt.Sprintf("%02d", t.Year()%100) }},
+	{"MMMM", func(t time.Time) string { return t.Month().String() }},
+	{"MMM", func(t time.Time) string { return t.Month().String()[:3] }},
+	{"MM", func(t time.Time) string { return fmt.Sprintf("%02d", int(t.Month())) }},
+	{"M", func(t time.Time) string { return strconv.Itoa(int(t.Month())) }},
+	{"dddd", func(t time.Time) string { return t.Weekday().String() }},
+	{"ddd", func(t time.Time) string { return t.Weekday().String()[:3] }},
+	{"dd", func(t time.Time) string { return fmt.Sprintf("%02d", t.Day()) }},
+	{"d", func(t time.Time) string { return strconv.Itoa(t.Day()) }},
+	{"HH", func(t time.Time) string { return fmt.Sprintf("%02d", t.Hour()) }},
+	{"H", func(t time.Time) string { return strconv.Itoa(t.Hour()) }},
+	{"hh", func(t time.Time) string { return fmt.Sprintf("%02d", hour12(t)) }},
+	{"h", func(t time.Time) string { return strconv.Itoa(hour12(t)) }},
+	{"mm", func(t time.Time) string { return fmt.Sprintf("%02d", t.Minute()) }},
+	{"m", func(t time.Time) string { return strconv.Itoa(t.Minute()) }},
+	{"ss", func(t time.Time) string { return fmt.Sprintf("%02d", t.Second()) }},
+	{"s", func(t time.Time) string { return strconv.Itoa(t.Second()) }},
+	{"fff", func(t time.Time) string { return fmt.Sprintf("%03d", t.Nanosecond()/1e6) }},
+	{"ff", func(t time.Time) string { return fmt.Sprintf("%02d", t.Nanosecond()/1e7) }},
+	{"f", func(t time.Time) string { return strconv.Itoa(t.Nanosecond() / 1e8) }},
+	{"tt", func(t time.Time) string { return t.Format("PM") }},
+}
+
+func hour12(t time.Time) int {
+	h := t.Hour() % 12
+	if h == 0 {
+		return 12
+	}
+	return h
+}
+
+// formatDotNetTime formats time with .NET custom format. Text in single quotes is copied as is.
+func formatDotNetTime(t time.Time, format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); {
+		if format[i] == '\'' {
+			end := strings.IndexByte(format[i+1:], '\'')
+			if end < 0 {
+				b.WriteString(format[i+1:])
+				break
+			}
+			b.WriteString(format[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+		matched := false
+		for _, tok := range dotNetTimeTokens {
+			if strings.HasPrefix(format[i:], tok.token) {
+				b.WriteString(tok.format(t))
+				i += len(tok.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(format[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// FunctionNames returns names of all functions available in expressions
+func FunctionNames() []string {
+	names := []string{"if"}
+	for name := range builtinFunctions {
+		names = append(names, name)
+	}
+	functionsMu.RLock()
+	for name := range customFunctions {
+		names = append(names, name)
+	}
+	functionsMu.RUnlock()
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,118 @@
+package cards
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	data := map[string]interface{}{
+		"name":  "Matt",
+		"count": 3,
+		"price": 1234.5,
+		"done":  false,
+		"user": map[string]interface{}{
+			"tags": []string{"a", "b", "c"},
+		},
+		"created": "2017-02-14T06:08:39Z",
+	}
+	cases := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"name", "Matt"},
+		{"user.tags[1]", "b"},
+		{"user['tags'][0]", "a"},
+		{"missing.path", nil},
+		{"count * 2 + 1", float64(7)},
+		{"(count + 1) * 2", float64(8)},
+		{"2 ^ 3 ^ 2", float64(512)},
+		{"-count", float64(-3)},
+		{"count % 2 == 1", true},
+		{"count > 2 && !done", true},
+		{"done || count < 1", false},
+		{"name != 'Bob'", true},
+		{"'Hello, ' + name", "Hello, Matt"},
+		{"name & count", "Matt3"},
+		{"`${name} has ${count} items`", "Matt has 3 items"},
+		{"if(count > 5, 'many', 'few')", "few"},
+		{"concat(name, '!', '!')", "Matt!!"},
+		{"count(user.tags)", float64(3)},
+		{"toUpper(name)", "MATT"},
+		{"toLower('ABC')", "abc"},
+		{"substring(name, 1, 2)", "at"},
+		{"coalesce(missing, null, 'default')", "default"},
+		{"json('{\"a\": [1, 2]}').a[1]", float64(2)},
+		{"formatNumber(price, 2)", "1,234.50"},
+		{"formatNumber(-0.004, 2)", "0.00"},
+		{"formatDateTime(created, 'dd MMM yyyy HH:mm')", "14 Feb 2017 06:08"},
+		{"formatDateTime(created)", "2017-02-14T06:08:39.000Z"},
+		{"formatDateTime(addDays(created, 1), 'dddd')", "Wednesday"},
+		{"join(user.tags, ', ', ' and ')", "a, b and c"},
+		{"contains(user.tags, 'c')", true},
+		{"empty(missing)", true},
+		{"max(1, count, 2)", float64(3)},
+		{"round(price / 1000, 1)", 1.2},
+		{"[1, 'a'][1]", "a"},
+		{"{a: count}.a", float64(3)},
+		{"string(user.tags)", `["a","b","c"]`},
+		{"user.tags[10000000000000000000]", nil},
+		{"user.tags[-1]", nil},
+		{"'héllo'.length", float64(5)},
+		{"length('héllo')", float64(5)},
+	}
+	for _, c := range cases {
+		got, err := Evaluate(c.expr, data)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("%s: expected %#v, got %#v", c.expr, c.expected, got)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"1 +",
+		"(1",
+		"'unterminated",
+		"unknownFunction(1)",
+		"1 / 0",
+		"toUpper()",
+		"name name",
+		"[1, 2][1.9]",
+		"[1, 2][-0.5]",
+		"formatNumber(1, 100000000)",
+		"formatNumber(1, -1)",
+		"substring('abc', 10000000000000000000)",
+	} {
+		if _, err := Evaluate(expr, nil); err == nil {
+			t.Errorf("%q: expected to have an error, got nil", expr)
+		}
+	}
+}
+
+func TestRegisterFunction(t *testing.T) {
+	RegisterFunction("testShout", func(args ...interface{}) (interface{}, error) {
+		return strings.ToUpper(stringify(args[0])) + "!", nil
+	})
+	e := MustParseExpression("testShout(name)")
+	got, err := e.Evaluate(map[string]string{"name": "hey"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "HEY!" {
+		t.Errorf("expected %q, got %q", "HEY!", got)
+	}
+	ok, err := MustParseExpression("exists(name)").EvaluateBool(map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("expected false, got true")
+	}
+}
@@ -0,0 +1,91 @@
+package cards
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// fallbackDrop is JSON value of fallback which drops the element
+const fallbackDrop = "drop"
+
+// Fallback describes what to do when the element is not supported by the client:
+// either render another element instead or drop the element ignoring any parent fallback.
+// In JSON it is either an element or the string "drop".
+type Fallback struct {
+	Element Node
+	Drop    bool
+}
+
+// FallbackDrop returns fallback which drops the element
+func FallbackDrop() *Fallback {
+	return &Fallback{Drop: true}
+}
+
+// FallbackElement returns fallback which renders n instead of the element
+func FallbackElement(n Node) *Fallback {
+	return &Fallback{Element: n}
+}
+
+// MarshalJSON encodes fallback as "drop" or fallback element
+func (f Fallback) MarshalJSON() ([]byte, error) {
+	if f.Drop {
+		return json.Marshal(fallbackDrop)
+	}
+	return json.Marshal(f.Element)
+}
+
+// UnmarshalJSON decodes "drop" or fallback element restoring its concrete type
+func (f *Fallback) UnmarshalJSON(data []byte) error {
+	return decodeJSON(data, f)
+}
+
+func (f *Fallback) check(r *reporter, owner Node) {
+	switch {
+	case f.Drop && f.Element != nil:
+		r.add(CodeInvalidValue, "fallback can't both drop the element and have fallback element")
+	case f.Drop:
+	case isNilNode(f.Element):
+		r.required(fmt.Sprintf("fallback must be an element or %q", fallbackDrop))
+	case owner.NodeType() == ColumnType && f.Element.NodeType() != ColumnType:
+		r.add(CodeInvalidValue, fmt.Sprintf("fallback of Column must be Column, got %s", f.Element.NodeType()))
+	case isAction(owner) != isAction(f.Element):
+		r.add(CodeInvalidValue, fmt.Sprintf("%s can't be fallback of %s", f.Element.NodeType(), owner.NodeType()))
+	}
+}
+
+// nodeFallback returns fallback of the element if it has one
+func nodeFallback(n interface{}) *Fallback {
+	f := structField(n, "Fallback")
+	if !f.IsValid() {
+		return nil
+	}
+	fb, _ := f.Interface().(*Fallback)
+	return fb
+}
+
+// prepareFallback checks fallback of the element and prepares fallback element
+func prepareFallback(n Node) error {
+	f := nodeFallback(n)
+	if f == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	f.check(&reporter{errs: &errs}, n)
+	if len(errs) > 0 {
+		return errors.New(errs[0].Message)
+	}
+	if f.Element == nil {
+		return nil
+	}
+	if err := prepareNode(f.Element); err != nil {
+		return fmt.Errorf("fallback: %w", err)
+	}
+	return nil
+}
+
+// isAction tells if the element is an action
+func isAction(n Node) bool {
+	return strings.HasPrefix(n.NodeType(), "Action.")
+}
@@ -0,0 +1,85 @@
+package cards
+
+import (
+	"strings"
+	"testing"
+)
+
+func newFallbackCard() *Card {
+	return New([]Node{
+		&Media{
+			Sources: []*MediaSource{{MimeType: "video/mp4", URL: "https://adaptivecards.io/content/cat.mp4"}},
+			Fallback: FallbackElement(&Image{
+				URL: "https://adaptivecards.io/content/cat.png",
+			}),
+		},
+		&RichTextBlock{
+			Inlines:  []*TextRun{{Text: "Rich text"}},
+			Fallback: FallbackDrop(),
+		},
+	}, []Node{
+		&ActionToggleVisibility{
+			Title:          "Toggle",
+			TargetElements: []TargetElement{{ElementID: "details"}},
+			Fallback:       FallbackElement(&ActionOpenURL{URL: "https://adaptivecards.io", Title: "Open"}),
+		},
+	}).WithVersion(Version12).WithSchema(DefaultSchema)
+}
+
+func TestFallbackCard(t *testing.T) {
+	fallbackJSON := mustReadFile("./test/fallback.json")
+	got, err := newFallbackCard().StringIndent("", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != fallbackJSON {
+		t.Errorf("expected:\n%s\nbut got:\n%s", fallbackJSON, got)
+	}
+}
+
+func TestParseFallback(t *testing.T) {
+	c, err := Parse(strings.NewReader(mustReadFile("./test/fallback.json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	media := c.Body[0].(*Media)
+	if _, ok := media.Fallback.Element.(*Image); !ok || media.Fallback.Drop {
+		t.Errorf("expected *Image fallback, got %+v", media.Fallback)
+	}
+	rich := c.Body[1].(*RichTextBlock)
+	if !rich.Fallback.Drop || rich.Fallback.Element != nil {
+		t.Errorf("expected drop fallback, got %+v", rich.Fallback)
+	}
+
+	_, err = Parse(strings.NewReader(`{"type":"AdaptiveCard","version":"1.2","body":[{"type":"TextBlock","text":"foo","fallback":"ignore"}]}`))
+	if err == nil {
+		t.Error("expected to have an error, got nil")
+	}
+}
+
+func TestFallbackValidation(t *testing.T) {
+	c := New([]Node{
+		&Media{
+			Sources:  []*MediaSource{{MimeType: "video/mp4", URL: "https://adaptivecards.io/content/cat.mp4"}},
+			Fallback: FallbackElement(&TextBlock{}),
+		},
+		&TextBlock{Text: "foo", Fallback: FallbackElement(&ActionSubmit{})},
+		&TextBlock{Text: "bar", Fallback: &Fallback{}},
+	}, nil)
+	if err := c.Prepare(); err == nil || err.Error() != "fallback: TextBlock text is required" {
+		t.Errorf("expected fallback error, got %v", err)
+	}
+
+	var got []string
+	for _, e := range c.Validate() {
+		got = append(got, e.Error())
+	}
+	expected := []string{
+		"/body/0/fallback: TextBlock text is required",
+		"/body/1/fallback: Action.Submit can't be fallback of TextBlock",
+		`/body/2/fallback: fallback must be an element or "drop"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected:\n%v\nbut got:\n%v", expected, got)
+	}
+}
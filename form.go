@@ -0,0 +1,370 @@
+package cards
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// FormOptions configures FormFromStruct
+type FormOptions struct {
+	Title       string                 // heading above inputs, no heading if empty
+	SubmitTitle string                 // title of Action.Submit, "Submit" if empty
+	SubmitData  map[string]interface{} // data of Action.Submit, e.g. to tell forms apart
+	Version     string                 // card version, Version13 if empty
+}
+
+// Choicer is implemented by enum-like types which are rendered as Input.ChoiceSet.
+// Choice values must be accepted by DecodeSubmission for the type.
+type Choicer interface {
+	Choices() []*InputChoice
+}
+
+var (
+	choicerType       = reflect.TypeOf((*Choicer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// FormFromStruct returns a card with labelled inputs for the fields of the struct v (or a pointer to it)
+// pre-populated with current values and Action.Submit. The submitted payload can be decoded back
+// with DecodeSubmission.
+//
+// Inputs are chosen by field type: strings become Input.Text, numbers Input.Number, bools Input.Toggle,
+// time.Time Input.Date, TimeOfDay and time.Duration Input.Time, types implementing Choicer and fields
+// with choices Input.ChoiceSet (multi-select for slices). Embedded structs are flattened.
+// Input ID is the first part of the `card` tag (then `json` tag or field name), other parts are options:
+//
+//	Name     string    `card:"name,label=Full name,required,maxLength=50,regex=^[A-Z]"`
+//	Comment  string    `card:"comment,multiline,placeholder=Anything else?"`
+//	Qty      int       `card:"qty,min=1,max=10"`
+//	Colors   []string  `card:"colors,choices=red|green|blue,style=expanded"`
+//	Start    time.Time `card:"start,time"`
+//	Internal string    `card:"-"`
+//
+// Option values can't contain commas except regex, which takes everything up to the next known option.
+func FormFromStruct(v interface{}, opts FormOptions) (*Card, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("FormFromStruct requires a struct, got nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("FormFromStruct requires a struct, got %s", rv.Type())
+	}
+	var body []Node
+	if opts.Title != "" {
+		body = append(body, &TextBlock{Text: opts.Title, Size: SizeMedium, Weight: WeightBolder, Wrap: TruePtr()})
+	}
+	inputs, err := formInputs(rv)
+	if err != nil {
+		return nil, err
+	}
+	body = append(body, inputs...)
+	c := New(body, []Node{&ActionSubmit{
+		Title: firstNonEmpty(opts.SubmitTitle, "Submit"),
+		Data:  opts.SubmitData,
+	}})
+	if opts.Version != "" {
+		c.Version = opts.Version
+	}
+	return c, nil
+}
+
+// formField is a parsed `card` tag
+type formField struct {
+	id      string
+	label   string
+	options map[string]string
+}
+
+func (f formField) has(option string) bool {
+	_, ok := f.options[option]
+	return ok
+}
+
+var formOptionNames = map[string]bool{
+	"label": true, "placeholder": true, "required": true, "regex": true, "maxLength": true,
+	"multiline": true, "style": true, "min": true, "max": true, "choices": true,
+	"date": true, "time": true,
+}
+
+// parseFormField parses `card` tag of the field, ok is false if the field is skipped
+func parseFormField(field reflect.StructField) (f formField, ok bool) {
+	f.id, ok = submissionKey(field)
+	if !ok {
+		return f, false
+	}
+	if f.id == "" {
+		f.id = field.Name
+	}
+	f.options = map[string]string{}
+	parts := strings.Split(field.Tag.Get("card"), ",")
+	last := ""
+	for _, p := range parts[1:] {
+		name, value := p, ""
+		if i := strings.IndexByte(p, '='); i >= 0 {
+			name, value = p[:i], p[i+1:]
+		}
+		if !formOptionNames[name] && last == "regex" {
+			f.options[last] += "," + p
+			continue
+		}
+		f.options[name] = value
+		last = name
+	}
+	f.label = firstNonEmpty(f.options["label"], humanize(field.Name))
+	return f, true
+}
+
+// formInputs returns inputs for the fields of the struct value
+func formInputs(rv reflect.Value) ([]Node, error) {
+	var res []Node
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		f, ok := parseFormField(field)
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		if key, _ := submissionKey(field); key == "" && field.Anonymous {
+			for fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv = reflect.Zero(fv.Type().Elem())
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct && fv.Type() != timeType && fv.Type() != timeOfDayType {
+				inputs, err := formInputs(fv)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, inputs...)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		n, err := formInput(f, fv)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+// formInput returns input for the field value
+func formInput(f formField, v reflect.Value) (Node, error) {
+	typ := v.Type()
+	for v.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		if v.IsNil() {
+			v = reflect.Zero(typ)
+		} else {
+			v = v.Elem()
+		}
+	}
+	var required *bool
+	if f.has("required") {
+		required = TruePtr()
+	}
+
+	multi := typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8
+	elem := typ
+	if multi {
+		elem = typ.Elem()
+	}
+	var choices []*InputChoice
+	switch {
+	case f.has("choices"):
+		for _, value := range strings.Split(f.options["choices"], "|") {
+			choices = append(choices, &InputChoice{Title: value, Value: value})
+		}
+	case elem.Implements(choicerType):
+		choices = reflect.Zero(elem).Interface().(Choicer).Choices()
+	case reflect.PtrTo(elem).Implements(choicerType):
+		choices = reflect.New(elem).Interface().(Choicer).Choices()
+	}
+	if choices != nil {
+		var values []string
+		if multi {
+			for i := 0; i < v.Len(); i++ {
+				values = append(values, formValue(v.Index(i)))
+			}
+		} else {
+			values = append(values, formValue(v))
+		}
+		input := &InputChoiceSet{
+			ID:          f.id,
+			Label:       f.label,
+			Choices:     choices,
+			Style:       ChoiceInputStyle(f.options["style"]),
+			Placeholder: f.options["placeholder"],
+			Value:       strings.Join(values, ","),
+			IsRequired:  required,
+		}
+		if multi {
+			input.IsMultiSelect = TruePtr()
+		}
+		// zero value of a single choice is "not selected" unless it is one of the choices
+		if !multi && !hasChoice(input, input.Value) {
+			input.Value = ""
+		}
+		return input, nil
+	}
+
+	switch {
+	case typ == timeType && f.has("time"):
+		t := v.Interface().(time.Time)
+		value := ""
+		if !t.IsZero() {
+			value = t.Format(InputTimeLayout)
+		}
+		return &InputTime{ID: f.id, Label: f.label, Min: f.options["min"], Max: f.options["max"],
+			Placeholder: f.options["placeholder"], Value: value, IsRequired: required}, nil
+	case typ == timeType:
+		t := v.Interface().(time.Time)
+		value := ""
+		if !t.IsZero() {
+			value = t.Format(InputDateLayout)
+		}
+		return &InputDate{ID: f.id, Label: f.label, Min: f.options["min"], Max: f.options["max"],
+			Placeholder: f.options["placeholder"], Value: value, IsRequired: required}, nil
+	case typ == timeOfDayType || typ == durationType:
+		value := ""
+		if !v.IsZero() {
+			value = formValue(v)
+		}
+		return &InputTime{ID: f.id, Label: f.label, Min: f.options["min"], Max: f.options["max"],
+			Placeholder: f.options["placeholder"], Value: value, IsRequired: required}, nil
+	case f.has("date"):
+		return &InputDate{ID: f.id, Label: f.label, Min: f.options["min"], Max: f.options["max"],
+			Placeholder: f.options["placeholder"], Value: formValue(v), IsRequired: required}, nil
+	case f.has("time"):
+		return &InputTime{ID: f.id, Label: f.label, Min: f.options["min"], Max: f.options["max"],
+			Placeholder: f.options["placeholder"], Value: formValue(v), IsRequired: required}, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		value := "false"
+		if v.Bool() {
+			value = "true"
+		}
+		return &InputToggle{ID: f.id, Title: f.label, Value: value, IsRequired: required}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		input := &InputNumber{ID: f.id, Label: f.label, Placeholder: f.options["placeholder"], IsRequired: required}
+		input.Value, _ = strconv.ParseFloat(formValue(v), 64)
+		var err error
+		if input.Min, err = formFloat(f, "min"); err != nil {
+			return nil, err
+		}
+		if input.Max, err = formFloat(f, "max"); err != nil {
+			return nil, err
+		}
+		return input, nil
+	case reflect.String:
+	default:
+		if !typ.Implements(textMarshalerType) {
+			return nil, fmt.Errorf("unsupported type %s", typ)
+		}
+	}
+	input := &InputText{
+		ID:          f.id,
+		Label:       f.label,
+		Placeholder: f.options["placeholder"],
+		Regex:       f.options["regex"],
+		Style:       TextInputStyle(f.options["style"]),
+		Value:       formValue(v),
+		IsRequired:  required,
+	}
+	if f.has("multiline") {
+		input.IsMultiline = TruePtr()
+	}
+	if s, ok := f.options["maxLength"]; ok {
+		max, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxLength %q", s)
+		}
+		input.MaxLength = max
+	}
+	return input, nil
+}
+
+// formFloat returns numeric option of the field, nil if it isn't set
+func formFloat(f formField, option string) (*float64, error) {
+	s, ok := f.options[option]
+	if !ok {
+		return nil, nil
+	}
+	res, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", option, s)
+	}
+	return &res, nil
+}
+
+// formValue returns the value as an input sends it
+func formValue(v reflect.Value) string {
+	switch {
+	case v.Type() == durationType:
+		d := time.Duration(v.Int())
+		return TimeOfDay{Hour: int(d / time.Hour), Minute: int(d % time.Hour / time.Minute), Second: int(d % time.Minute / time.Second)}.String()
+	case v.Type().Implements(textMarshalerType):
+		data, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return formatFloat(v.Float())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	}
+	return ""
+}
+
+// humanize turns field name into a label: DueDate becomes "Due date", HTTPPort "HTTP port"
+func humanize(name string) string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		if i < len(runes) && !(unicode.IsUpper(runes[i]) && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			continue
+		}
+		word := string(runes[start:i])
+		if len(words) > 0 && !isUpperWord(word) {
+			word = strings.ToLower(word)
+		}
+		words = append(words, word)
+		start = i
+	}
+	return strings.Join(words, " ")
+}
+
+func isUpperWord(s string) bool {
+	return len(s) > 1 && strings.ToUpper(s) == s
+}
@@ -0,0 +1,156 @@
+package cards
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testSeverity string
+
+func (testSeverity) Choices() []*InputChoice {
+	return []*InputChoice{{Title: "Low", Value: "low"}, {Title: "High", Value: "high"}}
+}
+
+type testReporter struct {
+	Reporter string `card:"reporter,required"`
+}
+
+type testIncident struct {
+	testReporter
+	Title    string         `card:"title,label=Summary,required,maxLength=80,regex=^.{3,80}$"`
+	Details  string         `card:"details,multiline,placeholder=What happened?"`
+	Severity testSeverity   `card:"severity"`
+	Tags     []string       `card:"tags,choices=db|network|ui,style=expanded"`
+	Affected int            `card:"affected,min=0,max=1000"`
+	Date     time.Time      `card:"date"`
+	Started  TimeOfDay      `card:"started"`
+	Resolved *bool          `json:"resolved"`
+	HTTPCode int            `card:",min=100,max=599"`
+	Internal string         `card:"-"`
+	Meta     map[string]int `card:"-"`
+}
+
+func TestFormFromStruct(t *testing.T) {
+	v := testIncident{
+		testReporter: testReporter{Reporter: "bob"},
+		Title:        "DB is down",
+		Severity:     "high",
+		Tags:         []string{"db", "network"},
+		Affected:     12,
+		Date:         time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+		Started:      TimeOfDay{Hour: 9, Minute: 30},
+	}
+	c, err := FormFromStruct(&v, FormOptions{Title: "Incident", SubmitData: map[string]interface{}{"action": "incident"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Node{
+		&TextBlock{Text: "Incident", Size: SizeMedium, Weight: WeightBolder, Wrap: TruePtr()},
+		&InputText{ID: "reporter", Label: "Reporter", Value: "bob", IsRequired: TruePtr()},
+		&InputText{ID: "title", Label: "Summary", Value: "DB is down", Regex: "^.{3,80}$", MaxLength: 80, IsRequired: TruePtr()},
+		&InputText{ID: "details", Label: "Details", Placeholder: "What happened?", IsMultiline: TruePtr()},
+		&InputChoiceSet{ID: "severity", Label: "Severity", Value: "high", Choices: testSeverity("").Choices()},
+		&InputChoiceSet{ID: "tags", Label: "Tags", Value: "db,network", IsMultiSelect: TruePtr(), Style: ChoiceInputStyleExpanded,
+			Choices: []*InputChoice{{Title: "db", Value: "db"}, {Title: "network", Value: "network"}, {Title: "ui", Value: "ui"}}},
+		&InputNumber{ID: "affected", Label: "Affected", Value: 12, Min: FloatPtr(0), Max: FloatPtr(1000)},
+		&InputDate{ID: "date", Label: "Date", Value: "2021-03-04"},
+		&InputTime{ID: "started", Label: "Started", Value: "09:30"},
+		&InputToggle{ID: "resolved", Title: "Resolved", Value: "false"},
+		&InputNumber{ID: "HTTPCode", Label: "HTTP code", Min: FloatPtr(100), Max: FloatPtr(599)},
+	}
+	if !reflect.DeepEqual(c.Body, expected) {
+		got, _ := json.MarshalIndent(c.Body, "", "  ")
+		t.Errorf("unexpected body:\n%s", got)
+	}
+	if submit, ok := c.Actions[0].(*ActionSubmit); !ok || submit.Title != "Submit" || submit.Data["action"] != "incident" {
+		t.Errorf("expected submit action but got %#v", c.Actions[0])
+	}
+	if err := c.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the form round trips through the simulator and DecodeSubmission
+	s, err := NewSimulator(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Set("severity", "low")
+	s.Set("resolved", true)
+	s.Set("HTTPCode", 503)
+	res, err := s.Click("Submit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, _ := json.Marshal(res.Data)
+	var got testIncident
+	if err := DecodeSubmission(c, payload, &got); err != nil {
+		t.Fatal(err)
+	}
+	v.Severity, v.Resolved, v.HTTPCode = "low", TruePtr(), 503
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("expected %+v but got %+v", v, got)
+	}
+}
+
+func TestFormFromStructOptions(t *testing.T) {
+	var v struct {
+		At      time.Time     `card:"at,time,min=09:00"`
+		Wait    time.Duration `card:"wait"`
+		Day     string        `card:"day,date"`
+		Email   string        `card:"email,style=email"`
+		Enabled bool          `card:"enabled,label=Turn it on"`
+	}
+	v.Wait = 90 * time.Minute
+	c, err := FormFromStruct(v, FormOptions{SubmitTitle: "Save", Version: Version12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Node{
+		&InputTime{ID: "at", Label: "At", Min: "09:00"},
+		&InputTime{ID: "wait", Label: "Wait", Value: "01:30"},
+		&InputDate{ID: "day", Label: "Day"},
+		&InputText{ID: "email", Label: "Email", Style: TextInputStyleEmail},
+		&InputToggle{ID: "enabled", Title: "Turn it on", Value: "false"},
+	}
+	if !reflect.DeepEqual(c.Body, expected) {
+		got, _ := json.MarshalIndent(c.Body, "", "  ")
+		t.Errorf("unexpected body:\n%s", got)
+	}
+	if c.Version != Version12 || c.Actions[0].(*ActionSubmit).Title != "Save" {
+		t.Errorf("options are not applied: %s %#v", c.Version, c.Actions[0])
+	}
+}
+
+func TestFormFromStructErrors(t *testing.T) {
+	for _, v := range []interface{}{
+		"text",
+		(*testIncident)(nil),
+		struct{ M map[string]int }{},
+		struct {
+			N int `card:"n,min=one"`
+		}{},
+		struct {
+			S string `card:"s,maxLength=x"`
+		}{},
+	} {
+		if _, err := FormFromStruct(v, FormOptions{}); err == nil {
+			t.Errorf("expected error for %T", v)
+		}
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Name":       "Name",
+		"DueDate":    "Due date",
+		"HTTPCode":   "HTTP code",
+		"UserID":     "User ID",
+		"StartedAt2": "Started at2",
+	} {
+		if got := humanize(name); got != expected {
+			t.Errorf("expected %q for %s but got %q", expected, name, got)
+		}
+	}
+}
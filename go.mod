@@ -1,3 +1,3 @@
 module github.com/DanielTitkov/go-adaptive-cards
 
-go 1.16
+go 1.18
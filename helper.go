@@ -5,6 +5,11 @@ func BoolPtr(b bool) *bool {
 	return &b
 }
 
+// FloatPtr returns pointer to float64
+func FloatPtr(f float64) *float64 {
+	return &f
+}
+
 // TruePtr returns pointer to true
 func TruePtr() *bool {
 	return BoolPtr(true)
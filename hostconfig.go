@@ -0,0 +1,493 @@
+package cards
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Bundled host config names
+const (
+	HostDefault    = "default"
+	HostTeamsLight = "teams-light"
+	HostTeamsDark  = "teams-dark"
+	HostOutlook    = "outlook"
+	HostWebChat    = "webchat"
+	HostWebex      = "webex"
+)
+
+// Show card action modes
+const (
+	ShowCardActionModeInline = "inline"
+	ShowCardActionModePopup  = "popup"
+)
+
+// Actions orientations
+const (
+	ActionsOrientationHorizontal = "horizontal"
+	ActionsOrientationVertical   = "vertical"
+)
+
+//go:embed hostconfigs/*.json
+var hostConfigFiles embed.FS
+
+// HostConfig describes how the host renders cards.
+// See https://docs.microsoft.com/en-us/adaptive-cards/rendering-cards/host-config
+type HostConfig struct {
+	SupportsInteractivity bool                  `json:"supportsInteractivity"`
+	FontFamily            string                `json:"fontFamily,omitempty"` // deprecated, use FontTypes
+	FontSizes             FontSizesConfig       `json:"fontSizes"`            // deprecated, use FontTypes
+	FontWeights           FontWeightsConfig     `json:"fontWeights"`          // deprecated, use FontTypes
+	FontTypes             FontTypesConfig       `json:"fontTypes"`
+	Spacing               SpacingConfig         `json:"spacing"`
+	Separator             SeparatorConfig       `json:"separator"`
+	ImageSizes            ImageSizesConfig      `json:"imageSizes"`
+	ContainerStyles       ContainerStylesConfig `json:"containerStyles"`
+	Actions               ActionsConfig         `json:"actions"`
+	AdaptiveCard          AdaptiveCardConfig    `json:"adaptiveCard"`
+	ImageSet              ImageSetConfig        `json:"imageSet"`
+	FactSet               FactSetConfig         `json:"factSet"`
+	Media                 MediaConfig           `json:"media"`
+	Inputs                InputsConfig          `json:"inputs"`
+	TextBlock             TextBlockConfig       `json:"textBlock"`
+	TextStyles            TextStylesConfig      `json:"textStyles"`
+	HostCapabilities      HostCapabilities      `json:"hostCapabilities,omitempty"`
+}
+
+// FontTypesConfig holds fonts for every font type
+type FontTypesConfig struct {
+	Default   FontTypeConfig `json:"default"`
+	Monospace FontTypeConfig `json:"monospace"`
+}
+
+// FontTypeConfig describes font family with its sizes and weights
+type FontTypeConfig struct {
+	FontFamily  string            `json:"fontFamily"`
+	FontSizes   FontSizesConfig   `json:"fontSizes"`
+	FontWeights FontWeightsConfig `json:"fontWeights"`
+}
+
+// FontSizesConfig holds font sizes in pixels
+type FontSizesConfig struct {
+	Small      int `json:"small"`
+	Default    int `json:"default"`
+	Medium     int `json:"medium"`
+	Large      int `json:"large"`
+	ExtraLarge int `json:"extraLarge"`
+}
+
+// FontWeightsConfig holds CSS-like font weights
+type FontWeightsConfig struct {
+	Lighter int `json:"lighter"`
+	Default int `json:"default"`
+	Bolder  int `json:"bolder"`
+}
+
+// SpacingConfig holds spacing in pixels
+type SpacingConfig struct {
+	Small      int `json:"small"`
+	Default    int `json:"default"`
+	Medium     int `json:"medium"`
+	Large      int `json:"large"`
+	ExtraLarge int `json:"extraLarge"`
+	Padding    int `json:"padding"`
+}
+
+// SeparatorConfig describes separator line
+type SeparatorConfig struct {
+	LineThickness int    `json:"lineThickness"`
+	LineColor     string `json:"lineColor"`
+}
+
+// ImageSizesConfig holds image widths in pixels
+type ImageSizesConfig struct {
+	Small  int `json:"small"`
+	Medium int `json:"medium"`
+	Large  int `json:"large"`
+}
+
+// ContainerStylesConfig holds colors of every container style
+type ContainerStylesConfig struct {
+	Default   ContainerStyleConfig `json:"default"`
+	Emphasis  ContainerStyleConfig `json:"emphasis"`
+	Good      ContainerStyleConfig `json:"good"`
+	Attention ContainerStyleConfig `json:"attention"`
+	Warning   ContainerStyleConfig `json:"warning"`
+	Accent    ContainerStyleConfig `json:"accent"`
+}
+
+// ContainerStyleConfig describes colors of a container style
+type ContainerStyleConfig struct {
+	BackgroundColor  string                 `json:"backgroundColor"`
+	BorderColor      string                 `json:"borderColor,omitempty"`
+	ForegroundColors ForegroundColorsConfig `json:"foregroundColors"`
+}
+
+// ForegroundColorsConfig holds text colors of a container style
+type ForegroundColorsConfig struct {
+	Default   ColorConfig `json:"default"`
+	Dark      ColorConfig `json:"dark"`
+	Light     ColorConfig `json:"light"`
+	Accent    ColorConfig `json:"accent"`
+	Good      ColorConfig `json:"good"`
+	Warning   ColorConfig `json:"warning"`
+	Attention ColorConfig `json:"attention"`
+}
+
+// ColorConfig describes regular and subtle variants of a color
+type ColorConfig struct {
+	Default         string               `json:"default"`
+	Subtle          string               `json:"subtle"`
+	HighlightColors HighlightColorConfig `json:"highlightColors"`
+}
+
+// HighlightColorConfig describes colors of highlighted text
+type HighlightColorConfig struct {
+	Default string `json:"default"`
+	Subtle  string `json:"subtle"`
+}
+
+// ActionsConfig describes how actions are rendered
+type ActionsConfig struct {
+	MaxActions         int            `json:"maxActions"`
+	Spacing            Spacing        `json:"spacing"`
+	ButtonSpacing      int            `json:"buttonSpacing"`
+	ShowCard           ShowCardConfig `json:"showCard"`
+	ActionsOrientation string         `json:"actionsOrientation"`
+	ActionAlignment    string         `json:"actionAlignment"` // left, center, right or stretch
+	IconPlacement      string         `json:"iconPlacement"`
+	IconSize           int            `json:"iconSize"`
+	AllowTitleToWrap   bool           `json:"allowTitleToWrap"`
+}
+
+// ShowCardConfig describes how Action.ShowCard cards are shown
+type ShowCardConfig struct {
+	ActionMode      string         `json:"actionMode"`
+	InlineTopMargin int            `json:"inlineTopMargin"`
+	Style           ContainerStyle `json:"style"`
+}
+
+// AdaptiveCardConfig holds card level settings
+type AdaptiveCardConfig struct {
+	AllowCustomStyle bool `json:"allowCustomStyle"`
+}
+
+// ImageSetConfig describes how ImageSet is rendered
+type ImageSetConfig struct {
+	ImageSize      ImageSize `json:"imageSize"`
+	MaxImageHeight int       `json:"maxImageHeight"`
+}
+
+// FactSetConfig describes how FactSet is rendered
+type FactSetConfig struct {
+	Title   TextConfig `json:"title"`
+	Value   TextConfig `json:"value"`
+	Spacing int        `json:"spacing"`
+}
+
+// TextConfig describes text style
+type TextConfig struct {
+	Size     TextSize   `json:"size"`
+	Weight   FontWeight `json:"weight"`
+	Color    Color      `json:"color"`
+	FontType FontType   `json:"fontType,omitempty"`
+	IsSubtle bool       `json:"isSubtle"`
+	Wrap     bool       `json:"wrap"`
+	MaxWidth int        `json:"maxWidth,omitempty"`
+}
+
+// MediaConfig describes how Media is rendered
+type MediaConfig struct {
+	DefaultPoster       string `json:"defaultPoster,omitempty"`
+	PlayButton          string `json:"playButton,omitempty"`
+	AllowInlinePlayback bool   `json:"allowInlinePlayback"`
+}
+
+// InputsConfig describes how input labels and errors are rendered
+type InputsConfig struct {
+	Label        InputLabelConfig   `json:"label"`
+	ErrorMessage ErrorMessageConfig `json:"errorMessage"`
+}
+
+// InputLabelConfig describes input labels
+type InputLabelConfig struct {
+	InputSpacing   Spacing     `json:"inputSpacing"`
+	RequiredInputs LabelConfig `json:"requiredInputs"`
+	OptionalInputs LabelConfig `json:"optionalInputs"`
+}
+
+// LabelConfig describes label text
+type LabelConfig struct {
+	Color    Color      `json:"color"`
+	IsSubtle bool       `json:"isSubtle"`
+	Size     TextSize   `json:"size"`
+	Weight   FontWeight `json:"weight"`
+	Suffix   string     `json:"suffix,omitempty"`
+}
+
+// ErrorMessageConfig describes input error messages
+type ErrorMessageConfig struct {
+	Size    TextSize   `json:"size"`
+	Spacing Spacing    `json:"spacing"`
+	Weight  FontWeight `json:"weight"`
+}
+
+// TextBlockConfig holds TextBlock settings
+type TextBlockConfig struct {
+	HeadingLevel int `json:"headingLevel"`
+}
+
+// TextStylesConfig holds predefined text styles
+type TextStylesConfig struct {
+	Heading      TextConfig `json:"heading"`
+	ColumnHeader TextConfig `json:"columnHeader"`
+}
+
+// DefaultHostConfig returns host config with default values of Adaptive Cards renderers
+func DefaultHostConfig() *HostConfig {
+	fontSizes := FontSizesConfig{Small: 12, Default: 14, Medium: 17, Large: 21, ExtraLarge: 26}
+	fontWeights := FontWeightsConfig{Lighter: 200, Default: 400, Bolder: 600}
+	foreground := ForegroundColorsConfig{
+		Default:   colorConfig("#333333", "#EE333333"),
+		Dark:      colorConfig("#000000", "#66000000"),
+		Light:     colorConfig("#FFFFFF", "#33000000"),
+		Accent:    colorConfig("#2E89FC", "#882E89FC"),
+		Good:      colorConfig("#54A254", "#DD54A254"),
+		Warning:   colorConfig("#E69500", "#DDE69500"),
+		Attention: colorConfig("#CC3300", "#DDCC3300"),
+	}
+	return &HostConfig{
+		SupportsInteractivity: true,
+		FontFamily:            "Segoe UI, Segoe, Segoe WP, Helvetica Neue, Helvetica, sans-serif",
+		FontSizes:             fontSizes,
+		FontWeights:           fontWeights,
+		FontTypes: FontTypesConfig{
+			Default: FontTypeConfig{
+				FontFamily:  "Segoe UI, Segoe, Segoe WP, Helvetica Neue, Helvetica, sans-serif",
+				FontSizes:   fontSizes,
+				FontWeights: fontWeights,
+			},
+			Monospace: FontTypeConfig{
+				FontFamily:  "Courier New, Courier, monospace",
+				FontSizes:   fontSizes,
+				FontWeights: fontWeights,
+			},
+		},
+		Spacing:    SpacingConfig{Small: 3, Default: 8, Medium: 20, Large: 30, ExtraLarge: 40, Padding: 15},
+		Separator:  SeparatorConfig{LineThickness: 1, LineColor: "#EEEEEE"},
+		ImageSizes: ImageSizesConfig{Small: 40, Medium: 80, Large: 160},
+		ContainerStyles: ContainerStylesConfig{
+			Default:   ContainerStyleConfig{BackgroundColor: "#FFFFFF", ForegroundColors: foreground},
+			Emphasis:  ContainerStyleConfig{BackgroundColor: "#08000000", ForegroundColors: foreground},
+			Good:      ContainerStyleConfig{BackgroundColor: "#CCFFCC", ForegroundColors: foreground},
+			Attention: ContainerStyleConfig{BackgroundColor: "#FFC5B2", ForegroundColors: foreground},
+			Warning:   ContainerStyleConfig{BackgroundColor: "#FFE2B2", ForegroundColors: foreground},
+			Accent:    ContainerStyleConfig{BackgroundColor: "#C7DEF9", ForegroundColors: foreground},
+		},
+		Actions: ActionsConfig{
+			MaxActions:    5,
+			Spacing:       SpacingDefault,
+			ButtonSpacing: 10,
+			ShowCard: ShowCardConfig{
+				ActionMode:      ShowCardActionModeInline,
+				InlineTopMargin: 16,
+				Style:           ContainerStyleEmphasis,
+			},
+			ActionsOrientation: ActionsOrientationHorizontal,
+			ActionAlignment:    string(HorizontalAlignmentLeft),
+			IconPlacement:      "leftOfTitle",
+			IconSize:           16,
+		},
+		ImageSet: ImageSetConfig{ImageSize: ImageSizeMedium, MaxImageHeight: 100},
+		FactSet: FactSetConfig{
+			Title:   TextConfig{Size: SizeDefault, Weight: WeightBolder, Color: ColorDefault, Wrap: true, MaxWidth: 150},
+			Value:   TextConfig{Size: SizeDefault, Weight: WeightDefault, Color: ColorDefault, Wrap: true},
+			Spacing: 10,
+		},
+		Media: MediaConfig{AllowInlinePlayback: true},
+		Inputs: InputsConfig{
+			Label: InputLabelConfig{
+				InputSpacing:   SpacingSmall,
+				RequiredInputs: LabelConfig{Color: ColorDefault, Size: SizeDefault, Weight: WeightDefault, Suffix: " *"},
+				OptionalInputs: LabelConfig{Color: ColorDefault, Size: SizeDefault, Weight: WeightDefault},
+			},
+			ErrorMessage: ErrorMessageConfig{Size: SizeDefault, Spacing: SpacingSmall, Weight: WeightDefault},
+		},
+		TextBlock: TextBlockConfig{HeadingLevel: 2},
+		TextStyles: TextStylesConfig{
+			Heading:      TextConfig{Size: SizeLarge, Weight: WeightBolder, Color: ColorDefault, FontType: FontTypeDefault},
+			ColumnHeader: TextConfig{Size: SizeDefault, Weight: WeightBolder, Color: ColorDefault, FontType: FontTypeDefault},
+		},
+	}
+}
+
+func colorConfig(color, subtle string) ColorConfig {
+	return ColorConfig{
+		Default:         color,
+		Subtle:          subtle,
+		HighlightColors: HighlightColorConfig{Default: "#22000000", Subtle: "#11000000"},
+	}
+}
+
+// ParseHostConfig reads host config JSON.
+// Values missing in JSON are taken from DefaultHostConfig.
+func ParseHostConfig(r io.Reader) (*HostConfig, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	hc := DefaultHostConfig()
+	if err := json.Unmarshal(data, hc); err != nil {
+		return nil, err
+	}
+	return hc, nil
+}
+
+// BundledHostConfig returns host config of a common host, e.g. HostTeamsLight.
+// Every call returns a new config which can be modified.
+func BundledHostConfig(name string) (*HostConfig, error) {
+	if name == HostDefault {
+		return DefaultHostConfig(), nil
+	}
+	f, err := hostConfigFiles.Open(path.Join("hostconfigs", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown host config %q", name)
+	}
+	defer f.Close()
+	return ParseHostConfig(f)
+}
+
+// BundledHostConfigs returns names of all bundled host configs
+func BundledHostConfigs() []string {
+	names := []string{HostDefault}
+	entries, _ := hostConfigFiles.ReadDir("hostconfigs")
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Bytes returns host config JSON as bytes
+func (hc *HostConfig) Bytes() ([]byte, error) {
+	return json.Marshal(hc)
+}
+
+// BytesIndent returns host config JSON as bytes with indentation
+func (hc *HostConfig) BytesIndent(prefix string, indent string) ([]byte, error) {
+	return json.MarshalIndent(hc, prefix, indent)
+}
+
+// SpacingPixels returns spacing in pixels, empty spacing is default
+func (hc *HostConfig) SpacingPixels(s Spacing) int {
+	switch strings.ToLower(string(s)) {
+	case "none":
+		return 0
+	case "small":
+		return hc.Spacing.Small
+	case "medium":
+		return hc.Spacing.Medium
+	case "large":
+		return hc.Spacing.Large
+	case "extralarge":
+		return hc.Spacing.ExtraLarge
+	case "padding":
+		return hc.Spacing.Padding
+	}
+	return hc.Spacing.Default
+}
+
+// FontTypeConfig returns config of the font type, empty font type is default
+func (hc *HostConfig) FontTypeConfig(t FontType) FontTypeConfig {
+	if strings.EqualFold(string(t), string(FontTypeMonospace)) {
+		return hc.FontTypes.Monospace
+	}
+	return hc.FontTypes.Default
+}
+
+// FontSize returns font size in pixels, empty size is default
+func (hc *HostConfig) FontSize(t FontType, s TextSize) int {
+	sizes := hc.FontTypeConfig(t).FontSizes
+	switch strings.ToLower(string(s)) {
+	case "small":
+		return sizes.Small
+	case "medium":
+		return sizes.Medium
+	case "large":
+		return sizes.Large
+	case "extralarge":
+		return sizes.ExtraLarge
+	}
+	return sizes.Default
+}
+
+// FontWeight returns CSS font weight, empty weight is default
+func (hc *HostConfig) FontWeight(t FontType, w FontWeight) int {
+	weights := hc.FontTypeConfig(t).FontWeights
+	switch strings.ToLower(string(w)) {
+	case "lighter":
+		return weights.Lighter
+	case "bolder":
+		return weights.Bolder
+	}
+	return weights.Default
+}
+
+// ContainerStyleConfig returns colors of the container style, empty style is default
+func (hc *HostConfig) ContainerStyleConfig(s ContainerStyle) ContainerStyleConfig {
+	switch strings.ToLower(string(s)) {
+	case "emphasis":
+		return hc.ContainerStyles.Emphasis
+	case "good":
+		return hc.ContainerStyles.Good
+	case "attention":
+		return hc.ContainerStyles.Attention
+	case "warning":
+		return hc.ContainerStyles.Warning
+	case "accent":
+		return hc.ContainerStyles.Accent
+	}
+	return hc.ContainerStyles.Default
+}
+
+// ForegroundColor returns text color in the container style, empty color is default
+func (hc *HostConfig) ForegroundColor(s ContainerStyle, c Color, subtle bool) string {
+	colors := hc.ContainerStyleConfig(s).ForegroundColors
+	color := colors.Default
+	switch strings.ToLower(string(c)) {
+	case "dark":
+		color = colors.Dark
+	case "light":
+		color = colors.Light
+	case "accent":
+		color = colors.Accent
+	case "good":
+		color = colors.Good
+	case "warning":
+		color = colors.Warning
+	case "attention":
+		color = colors.Attention
+	}
+	if subtle {
+		return color.Subtle
+	}
+	return color.Default
+}
+
+// ImageSizePixels returns image width in pixels, 0 for auto and stretch sizes
+func (hc *HostConfig) ImageSizePixels(s ImageSize) int {
+	switch strings.ToLower(string(s)) {
+	case "small":
+		return hc.ImageSizes.Small
+	case "medium":
+		return hc.ImageSizes.Medium
+	case "large":
+		return hc.ImageSizes.Large
+	}
+	return 0
+}
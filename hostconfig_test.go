@@ -0,0 +1,68 @@
+package cards
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBundledHostConfigs(t *testing.T) {
+	expected := []string{HostDefault, HostOutlook, HostTeamsDark, HostTeamsLight, HostWebChat, HostWebex}
+	if got := BundledHostConfigs(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for _, name := range expected {
+		hc, err := BundledHostConfig(name)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if hc.FontTypes.Default.FontSizes.Default == 0 || hc.ContainerStyles.Good.ForegroundColors.Default.Default == "" {
+			t.Errorf("%s: expected defaults to be filled", name)
+		}
+		data, err := hc.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseHostConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(hc, parsed) {
+			t.Errorf("%s: config is changed by JSON round trip", name)
+		}
+	}
+
+	if _, err := BundledHostConfig("foo"); err == nil {
+		t.Error("expected to have an error, got nil")
+	}
+}
+
+func TestParseHostConfig(t *testing.T) {
+	hc, err := ParseHostConfig(strings.NewReader(`{
+		"supportsInteractivity": false,
+		"spacing": {"small": 5},
+		"actions": {"maxActions": 3},
+		"hostCapabilities": {"acTest": "1.0"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults := DefaultHostConfig()
+	if hc.SupportsInteractivity {
+		t.Error("expected interactivity to be disabled")
+	}
+	if hc.Spacing.Small != 5 || hc.Spacing.Default != defaults.Spacing.Default {
+		t.Errorf("expected spacing to be merged with defaults, got %+v", hc.Spacing)
+	}
+	if hc.Actions.MaxActions != 3 || hc.Actions.ShowCard != defaults.Actions.ShowCard {
+		t.Errorf("expected actions to be merged with defaults, got %+v", hc.Actions)
+	}
+	if !hc.HostCapabilities.Supports("acTest", "1.0") {
+		t.Error("expected host capabilities to be loaded")
+	}
+
+	if _, err := ParseHostConfig(strings.NewReader(`{"spacing": 1}`)); err == nil {
+		t.Error("expected to have an error, got nil")
+	}
+}
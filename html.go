@@ -0,0 +1,1021 @@
+package cards
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// HTMLRenderer renders cards as self-contained HTML fragments styled by host config.
+// Action.ShowCard, Action.ToggleVisibility and Action.Submit work via small inline script,
+// submit dispatches "ac-submit" DOM event with action data merged with input values.
+type HTMLRenderer struct {
+	Config   *HostConfig // nil means DefaultHostConfig
+	IDPrefix string      // prefix of generated ids of letters, digits, "_" and "-", "ac" by default; use unique prefixes for cards on the same page
+}
+
+// RenderHTML renders the card as HTML using the host config, nil config means DefaultHostConfig
+func RenderHTML(c *Card, hc *HostConfig) (string, error) {
+	r := &HTMLRenderer{Config: hc}
+	return r.Render(c)
+}
+
+// Render renders the card as HTML
+func (r *HTMLRenderer) Render(c *Card) (string, error) {
+	var b strings.Builder
+	if err := r.RenderTo(&b, c); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// RenderTo renders the card as HTML and writes it to w.
+// Elements which requirements are not satisfied by host capabilities are resolved to their fallback.
+func (r *HTMLRenderer) RenderTo(w io.Writer, c *Card) error {
+	if err := c.Prepare(); err != nil {
+		return err
+	}
+	hc := r.Config
+	if hc == nil {
+		hc = DefaultHostConfig()
+	}
+	prefix := r.IDPrefix
+	if prefix == "" {
+		prefix = "ac"
+	}
+	// prefix goes to the stylesheet and the script as is
+	if strings.TrimLeft(prefix, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789_-") != "" {
+		return fmt.Errorf("id prefix %q must only have letters, digits, _ and -", prefix)
+	}
+	resolved, _ := Resolve(c, hc.HostCapabilities)
+	h := &htmlWriter{hc: hc, prefix: prefix}
+	h.card(resolved)
+	_, err := io.WriteString(w, h.b.String())
+	return err
+}
+
+// htmlWriter holds rendering state
+type htmlWriter struct {
+	hc     *HostConfig
+	prefix string
+	b      strings.Builder
+	style  ContainerStyle // style of the closest container
+	seq    int
+}
+
+// htmlBox collects inline style and attributes of element root tag
+type htmlBox struct {
+	css   []string
+	attrs []string
+}
+
+func (b *htmlBox) style(format string, args ...interface{}) {
+	b.css = append(b.css, fmt.Sprintf(format, args...))
+}
+
+func (b *htmlBox) attr(name, value string) {
+	b.attrs = append(b.attrs, name+`="`+html.EscapeString(value)+`"`)
+}
+
+// color adds the color style property if the color is valid
+func (b *htmlBox) color(name, color string) {
+	if c := cssColor(color); c != "" {
+		b.style("%s:%s", name, c)
+	}
+}
+
+// urlAttr adds the attribute if the URL is safe to use in the page (http, https, mailto or tel)
+func (b *htmlBox) urlAttr(name, url string) {
+	if isSafeURL(url) {
+		b.attr(name, url)
+	}
+}
+
+func (b *htmlBox) flag(name string) {
+	b.attrs = append(b.attrs, name)
+}
+
+func (b *htmlBox) String() string {
+	var s strings.Builder
+	if len(b.css) > 0 {
+		s.WriteString(` style="` + html.EscapeString(strings.Join(b.css, ";")) + `"`)
+	}
+	for _, a := range b.attrs {
+		s.WriteString(" " + a)
+	}
+	return s.String()
+}
+
+func (h *htmlWriter) write(parts ...string) {
+	for _, p := range parts {
+		h.b.WriteString(p)
+	}
+}
+
+func (h *htmlWriter) open(tag, class string, box *htmlBox) {
+	h.write("<", tag, ` class="`, class, `"`, box.String(), ">")
+}
+
+func (h *htmlWriter) nextID(kind string) string {
+	h.seq++
+	return fmt.Sprintf("%s-%s-%d", h.prefix, kind, h.seq)
+}
+
+func (h *htmlWriter) card(c *Card) {
+	style := h.hc.ContainerStyleConfig(ContainerStyleDefault)
+	font := h.hc.FontTypeConfig(FontTypeDefault)
+	h.write("<style>", h.css(), "</style>")
+	box := &htmlBox{}
+	box.attr("id", h.prefix)
+	box.style("font-family:%s", font.FontFamily)
+	box.style("font-size:%dpx", font.FontSizes.Default)
+	box.color("color", style.ForegroundColors.Default.Default)
+	box.color("background-color", style.BackgroundColor)
+	box.style("padding:%dpx", h.hc.Spacing.Padding)
+	if c.MinHeight != "" {
+		box.style("min-height:%s", c.MinHeight)
+	}
+	h.background(box, c.BackgroundImage)
+	h.verticalContent(box, c.VerticalContentAlignment)
+	h.selectAction(box, c.SelectAction)
+	if c.Lang != "" {
+		box.attr("lang", c.Lang)
+	}
+	h.open("div", "ac-card", box)
+	h.body(c.Body, c.Actions)
+	h.write("</div>")
+	h.write("<script>", htmlScript, "</script>")
+}
+
+// body renders card or container items followed by actions
+func (h *htmlWriter) body(items []Node, actions []Node) {
+	first := h.items(items)
+	if len(actions) > 0 {
+		box := &htmlBox{}
+		if !first {
+			box.style("margin-top:%dpx", h.hc.SpacingPixels(h.hc.Actions.Spacing))
+		}
+		h.actions(actions, box)
+	}
+}
+
+// items renders elements stacked vertically and tells if nothing is rendered
+func (h *htmlWriter) items(nodes []Node) bool {
+	first := true
+	for _, n := range nodes {
+		if h.element(n, first) {
+			first = false
+		}
+	}
+	return first
+}
+
+// element renders single element and tells if anything is rendered
+func (h *htmlWriter) element(n Node, first bool) bool {
+	if isNilNode(n) {
+		return false
+	}
+	box := h.layout(n, first)
+	switch n := n.(type) {
+	case *TextBlock:
+		h.textBlock(n, box)
+	case *Image:
+		h.image(n, box)
+	case *Media:
+		h.media(n, box)
+	case *RichTextBlock:
+		h.richTextBlock(n, box)
+	case *Container:
+		h.container(n, box)
+	case *ColumnSet:
+		h.columnSet(n, box)
+	case *FactSet:
+		h.factSet(n, box)
+	case *ImageSet:
+		h.imageSet(n, box)
+	case *ActionSet:
+		return h.actions(n.Actions, box)
+	case *InputText, *InputNumber, *InputDate, *InputTime, *InputToggle, *InputChoiceSet:
+		return h.input(n, box)
+	default:
+		// unknown element is replaced with its fallback
+		if f := nodeFallback(n); f != nil && f.Element != nil {
+			return h.element(f.Element, first)
+		}
+		h.write("<!-- unsupported element ", html.EscapeString(n.NodeType()), " -->")
+		return false
+	}
+	return true
+}
+
+// layout returns box with common element properties: spacing, separator, visibility, id and height
+func (h *htmlWriter) layout(n Node, first bool) *htmlBox {
+	box := &htmlBox{}
+	if !first {
+		spacing, _ := fieldValue(n, "Spacing").(Spacing)
+		px := h.hc.SpacingPixels(spacing)
+		if sep, _ := fieldValue(n, "Separator").(*bool); sep != nil && *sep {
+			box.style("border-top:%dpx solid %s", h.hc.Separator.LineThickness, cssColor(h.hc.Separator.LineColor))
+			box.style("margin-top:%dpx", px/2)
+			box.style("padding-top:%dpx", px-px/2)
+		} else if px > 0 {
+			box.style("margin-top:%dpx", px)
+		}
+	}
+	if height, ok := fieldValue(n, "Height").(BlockElementHeight); ok && strings.EqualFold(string(height), string(HeightStretch)) {
+		box.style("flex:1 1 auto")
+	}
+	if id := nodeID(n); id != "" {
+		box.attr("data-ac-id", id)
+	}
+	if visible, _ := fieldValue(n, "IsVisible").(*bool); visible != nil && !*visible {
+		box.flag("hidden")
+	}
+	return box
+}
+
+func (h *htmlWriter) textBlock(n *TextBlock, box *htmlBox) {
+	h.text(box, n.FontType, n.Size, n.Weight, n.Color, isTrue(n.IsSubtle))
+	if align := cssTextAlign(n.HorizontalAlignment); align != "" {
+		box.style("text-align:%s", align)
+	}
+	switch {
+	case !isTrue(n.Wrap):
+		box.style("white-space:nowrap;overflow:hidden;text-overflow:ellipsis")
+	case n.MaxLines > 0:
+		box.style("display:-webkit-box;-webkit-line-clamp:%d;-webkit-box-orient:vertical;overflow:hidden", n.MaxLines)
+	}
+	if strings.EqualFold(string(n.Weight), string(WeightBolder)) &&
+		(strings.EqualFold(string(n.Size), string(SizeLarge)) || strings.EqualFold(string(n.Size), string(SizeExtraLarge))) {
+		box.attr("role", "heading")
+		box.attr("aria-level", strconv.Itoa(h.hc.TextBlock.HeadingLevel))
+	}
+	h.open("div", "ac-textblock", box)
+	h.write(markdownHTML(n.Text), "</div>")
+}
+
+// text adds font and color styles
+func (h *htmlWriter) text(box *htmlBox, fontType FontType, size TextSize, weight FontWeight, color Color, subtle bool) {
+	box.color("color", h.hc.ForegroundColor(h.style, color, subtle))
+	box.style("font-size:%dpx", h.hc.FontSize(fontType, size))
+	box.style("font-weight:%d", h.hc.FontWeight(fontType, weight))
+	if strings.EqualFold(string(fontType), string(FontTypeMonospace)) {
+		box.style("font-family:%s", h.hc.FontTypes.Monospace.FontFamily)
+	}
+}
+
+func (h *htmlWriter) image(n *Image, box *htmlBox) {
+	box.style("display:flex")
+	if align := cssFlexAlign(n.HorizontalAlignment); align != "" {
+		box.style("justify-content:%s", align)
+	}
+	h.open("div", "ac-image", box)
+	img := &htmlBox{}
+	img.urlAttr("src", n.URL)
+	img.attr("alt", n.AltText)
+	switch {
+	case n.Width != "":
+		img.style("width:%s", n.Width)
+	case strings.EqualFold(string(n.Size), string(ImageSizeStretch)):
+		img.style("width:100%%")
+	case h.hc.ImageSizePixels(n.Size) > 0:
+		img.style("width:%dpx", h.hc.ImageSizePixels(n.Size))
+	default:
+		img.style("max-width:100%%")
+	}
+	if _, err := ParsePixels(string(n.Height)); err == nil {
+		img.style("height:%s", n.Height)
+	}
+	if n.BackgroundColor != "" {
+		img.color("background-color", n.BackgroundColor)
+	}
+	if strings.EqualFold(string(n.Style), string(ImageStylePerson)) {
+		img.style("border-radius:50%%")
+	}
+	h.selectAction(img, n.SelectAction)
+	h.write("<img", img.String(), "></div>")
+}
+
+func (h *htmlWriter) media(n *Media, box *htmlBox) {
+	h.open("div", "ac-media", box)
+	if !h.hc.Media.AllowInlinePlayback {
+		poster := n.Poster
+		if poster == "" {
+			poster = h.hc.Media.DefaultPoster
+		}
+		link := &htmlBox{}
+		if len(n.Sources) > 0 {
+			link.urlAttr("href", n.Sources[0].URL)
+		}
+		link.attr("target", "_blank")
+		link.attr("rel", "noopener")
+		h.write("<a", link.String(), ">")
+		if poster != "" {
+			img := &htmlBox{}
+			img.urlAttr("src", poster)
+			img.attr("alt", n.AltText)
+			img.style("width:100%%")
+			h.write("<img", img.String(), ">")
+		} else {
+			h.write(html.EscapeString(firstNonEmpty(n.AltText, "Play media")))
+		}
+		h.write("</a></div>")
+		return
+	}
+	tag := "audio"
+	for _, s := range n.Sources {
+		if !strings.HasPrefix(strings.ToLower(s.MimeType), "audio/") {
+			tag = "video"
+		}
+	}
+	player := &htmlBox{}
+	player.flag("controls")
+	player.attr("preload", "none")
+	if n.Poster != "" && tag == "video" {
+		player.urlAttr("poster", n.Poster)
+	}
+	if n.AltText != "" {
+		player.attr("aria-label", n.AltText)
+	}
+	player.style("width:100%%")
+	h.write("<", tag, player.String(), ">")
+	for _, s := range n.Sources {
+		source := &htmlBox{}
+		source.urlAttr("src", s.URL)
+		source.attr("type", s.MimeType)
+		h.write("<source", source.String(), ">")
+	}
+	h.write("</", tag, "></div>")
+}
+
+func (h *htmlWriter) richTextBlock(n *RichTextBlock, box *htmlBox) {
+	if align := cssTextAlign(n.HorizontalAlignment); align != "" {
+		box.style("text-align:%s", align)
+	}
+	h.open("p", "ac-richtextblock", box)
+	for _, run := range n.Inlines {
+		span := &htmlBox{}
+		h.text(span, run.FontType, run.Size, run.Weight, run.Color, isTrue(run.IsSubtle))
+		if isTrue(run.Italic) {
+			span.style("font-style:italic")
+		}
+		var decorations []string
+		if isTrue(run.Strikethrough) {
+			decorations = append(decorations, "line-through")
+		}
+		if isTrue(run.Underline) {
+			decorations = append(decorations, "underline")
+		}
+		if len(decorations) > 0 {
+			span.style("text-decoration:%s", strings.Join(decorations, " "))
+		}
+		if isTrue(run.Highlight) {
+			colors := h.hc.ContainerStyleConfig(h.style).ForegroundColors.Default.HighlightColors
+			span.color("background-color", colors.Default)
+		}
+		h.selectAction(span, run.SelectAction)
+		h.write("<span", span.String(), ">", html.EscapeString(run.Text), "</span>")
+	}
+	h.write("</p>")
+}
+
+func (h *htmlWriter) container(n *Container, box *htmlBox) {
+	box.style("display:flex;flex-direction:column")
+	prev := h.styled(box, n.Style, isTrue(n.Bleed))
+	defer func() { h.style = prev }()
+	if n.MinHeight != "" {
+		box.style("min-height:%s", n.MinHeight)
+	}
+	h.background(box, n.BackgroundImage)
+	h.verticalContent(box, n.VerticalContentAlignment)
+	h.selectAction(box, n.SelectAction)
+	h.open("div", "ac-container", box)
+	h.items(n.Items)
+	h.write("</div>")
+}
+
+// styled applies container style and returns previous style to restore
+func (h *htmlWriter) styled(box *htmlBox, style ContainerStyle, bleed bool) ContainerStyle {
+	prev := h.style
+	if style == "" || strings.EqualFold(string(style), string(h.style)) {
+		return prev
+	}
+	h.style = style
+	box.color("background-color", h.hc.ContainerStyleConfig(style).BackgroundColor)
+	box.style("padding:%dpx", h.hc.Spacing.Padding)
+	if bleed {
+		box.style("margin-left:-%dpx;margin-right:-%dpx", h.hc.Spacing.Padding, h.hc.Spacing.Padding)
+	}
+	return prev
+}
+
+func (h *htmlWriter) columnSet(n *ColumnSet, box *htmlBox) {
+	box.style("display:flex")
+	if align := cssFlexAlign(n.HorizontalAlignment); align != "" {
+		box.style("justify-content:%s", align)
+	}
+	prev := h.styled(box, n.Style, isTrue(n.Bleed))
+	defer func() { h.style = prev }()
+	if n.MinHeight != "" {
+		box.style("min-height:%s", n.MinHeight)
+	}
+	h.selectAction(box, n.SelectAction)
+	h.open("div", "ac-columnset", box)
+	for i, c := range n.Columns {
+		h.column(c, i == 0, i == len(n.Columns)-1)
+	}
+	h.write("</div>")
+}
+
+func (h *htmlWriter) column(c *Column, first, last bool) {
+	box := &htmlBox{}
+	width := c.Width
+	if width == "" {
+		width = ColumnWidthStretch
+	}
+	switch px, err := ParsePixels(string(width)); {
+	case err == nil:
+		box.style("flex:0 0 %dpx", px)
+	case strings.EqualFold(string(width), string(ColumnWidthAuto)):
+		box.style("flex:0 1 auto")
+	case strings.EqualFold(string(width), string(ColumnWidthStretch)):
+		box.style("flex:1 1 0;min-width:0")
+	default:
+		weight, _ := strconv.ParseFloat(string(width), 64)
+		box.style("flex:%s 1 0;min-width:0", strconv.FormatFloat(weight, 'f', -1, 64))
+	}
+	box.style("display:flex;flex-direction:column")
+	if !first {
+		px := h.hc.SpacingPixels(c.Spacing)
+		if isTrue(c.Separator) {
+			box.style("border-left:%dpx solid %s", h.hc.Separator.LineThickness, cssColor(h.hc.Separator.LineColor))
+			box.style("margin-left:%dpx;padding-left:%dpx", px/2, px-px/2)
+		} else if px > 0 {
+			box.style("margin-left:%dpx", px)
+		}
+	}
+	// column bleeds only through the edges of the column set
+	prev := h.styled(box, c.Style, false)
+	defer func() { h.style = prev }()
+	if h.style != prev && isTrue(c.Bleed) {
+		if first {
+			box.style("margin-left:-%dpx", h.hc.Spacing.Padding)
+		}
+		if last {
+			box.style("margin-right:-%dpx", h.hc.Spacing.Padding)
+		}
+	}
+	if c.MinHeight != "" {
+		box.style("min-height:%s", c.MinHeight)
+	}
+	h.background(box, c.BackgroundImage)
+	h.verticalContent(box, c.VerticalContentAlignment)
+	if c.ID != "" {
+		box.attr("data-ac-id", c.ID)
+	}
+	if c.IsVisible != nil && !*c.IsVisible {
+		box.flag("hidden")
+	}
+	h.selectAction(box, c.SelectAction)
+	h.open("div", "ac-column", box)
+	h.items(c.Items)
+	h.write("</div>")
+}
+
+func (h *htmlWriter) factSet(n *FactSet, box *htmlBox) {
+	h.open("table", "ac-factset", box)
+	h.write("<tbody>")
+	for _, f := range n.Facts {
+		title := &htmlBox{}
+		h.textConfig(title, h.hc.FactSet.Title)
+		title.style("padding-right:%dpx", h.hc.FactSet.Spacing)
+		value := &htmlBox{}
+		h.textConfig(value, h.hc.FactSet.Value)
+		h.write("<tr><th", title.String(), ">", markdownInlineHTML(f.Title), "</th>")
+		h.write("<td", value.String(), ">", markdownInlineHTML(f.Value), "</td></tr>")
+	}
+	h.write("</tbody></table>")
+}
+
+// textConfig adds styles of host config text style
+func (h *htmlWriter) textConfig(box *htmlBox, t TextConfig) {
+	h.text(box, t.FontType, t.Size, t.Weight, t.Color, t.IsSubtle)
+	if t.MaxWidth > 0 {
+		box.style("max-width:%dpx", t.MaxWidth)
+	}
+	if !t.Wrap {
+		box.style("white-space:nowrap")
+	}
+}
+
+func (h *htmlWriter) imageSet(n *ImageSet, box *htmlBox) {
+	size := n.ImageSize
+	if size == "" {
+		size = h.hc.ImageSet.ImageSize
+	}
+	box.style("display:flex;flex-wrap:wrap;gap:%dpx", h.hc.Spacing.Default)
+	h.open("div", "ac-imageset", box)
+	for _, img := range n.Images {
+		i := &htmlBox{}
+		i.urlAttr("src", img.URL)
+		i.attr("alt", img.AltText)
+		if px := h.hc.ImageSizePixels(size); px > 0 {
+			i.style("width:%dpx", px)
+		}
+		i.style("max-height:%dpx;object-fit:contain", h.hc.ImageSet.MaxImageHeight)
+		h.selectAction(i, img.SelectAction)
+		h.write("<img", i.String(), ">")
+	}
+	h.write("</div>")
+}
+
+// input renders input with its label and error message
+func (h *htmlWriter) input(n Node, box *htmlBox) bool {
+	if !h.hc.SupportsInteractivity {
+		return false
+	}
+	id := nodeID(n)
+	controlID := h.prefix + "-input-" + id
+	required := false
+	if r, _ := fieldValue(n, "IsRequired").(*bool); r != nil {
+		required = *r
+	}
+	h.open("div", "ac-input", box)
+	if label := fieldString(n, "Label"); label != "" {
+		cfg := h.hc.Inputs.Label.OptionalInputs
+		if required {
+			cfg = h.hc.Inputs.Label.RequiredInputs
+		}
+		l := &htmlBox{}
+		l.attr("for", controlID)
+		h.text(l, FontTypeDefault, cfg.Size, cfg.Weight, cfg.Color, cfg.IsSubtle)
+		l.style("display:block;margin-bottom:%dpx", h.hc.SpacingPixels(h.hc.Inputs.Label.InputSpacing))
+		h.write("<label", l.String(), ">", html.EscapeString(label))
+		if required && cfg.Suffix != "" {
+			suffix := &htmlBox{}
+			suffix.color("color", h.hc.ForegroundColor(h.style, ColorAttention, false))
+			h.write("<span", suffix.String(), ">", html.EscapeString(cfg.Suffix), "</span>")
+		}
+		h.write("</label>")
+	}
+	control := &htmlBox{}
+	control.attr("id", controlID)
+	control.attr("name", id)
+	control.attr("data-ac-input", id)
+	if required {
+		control.flag("required")
+	}
+	switch n := n.(type) {
+	case *InputText:
+		h.inputText(n, control)
+	case *InputNumber:
+		control.attr("type", "number")
+		if n.Min != nil {
+			control.attr("min", formatFloat(*n.Min))
+		}
+		if n.Max != nil {
+			control.attr("max", formatFloat(*n.Max))
+		}
+		if n.Value != 0 {
+			control.attr("value", formatFloat(n.Value))
+		}
+		placeholder(control, n.Placeholder)
+		h.write("<input", control.String(), ">")
+	case *InputDate:
+		h.inputRange(control, "date", n.Min, n.Max, n.Value, n.Placeholder)
+	case *InputTime:
+		h.inputRange(control, "time", n.Min, n.Max, n.Value, n.Placeholder)
+	case *InputToggle:
+		h.inputToggle(n, control)
+	case *InputChoiceSet:
+		h.inputChoiceSet(n, control, controlID)
+	}
+	if msg := fieldString(n, "ErrorMessage"); msg != "" {
+		e := &htmlBox{}
+		cfg := h.hc.Inputs.ErrorMessage
+		h.text(e, FontTypeDefault, cfg.Size, cfg.Weight, ColorAttention, false)
+		e.style("margin-top:%dpx", h.hc.SpacingPixels(cfg.Spacing))
+		e.attr("role", "alert")
+		e.flag("hidden")
+		h.write(`<div class="ac-input-error"`, e.String(), ">", html.EscapeString(msg), "</div>")
+	}
+	h.write("</div>")
+	return true
+}
+
+func (h *htmlWriter) inputText(n *InputText, control *htmlBox) {
+	placeholder(control, n.Placeholder)
+	if n.MaxLength > 0 {
+		control.attr("maxlength", strconv.FormatInt(n.MaxLength, 10))
+	}
+	if n.Regex != "" {
+		control.attr("pattern", n.Regex)
+	}
+	inline := h.hc.SupportsInteractivity && !isNilNode(n.InlineAction)
+	if inline {
+		h.write(`<div class="ac-input-row" style="display:flex;gap:`, strconv.Itoa(h.hc.Spacing.Small), `px">`)
+	}
+	if isTrue(n.IsMultiline) {
+		control.attr("rows", "3")
+		h.write("<textarea", control.String(), ">", html.EscapeString(n.Value), "</textarea>")
+	} else {
+		style := string(n.Style)
+		if style == "" {
+			style = string(TextInputStyleText)
+		}
+		control.attr("type", strings.ToLower(style))
+		if n.Value != "" {
+			control.attr("value", n.Value)
+		}
+		h.write("<input", control.String(), ">")
+	}
+	if inline {
+		h.button(n.InlineAction)
+		h.write("</div>")
+	}
+}
+
+func (h *htmlWriter) inputRange(control *htmlBox, typ, min, max, value, text string) {
+	control.attr("type", typ)
+	if min != "" {
+		control.attr("min", min)
+	}
+	if max != "" {
+		control.attr("max", max)
+	}
+	if value != "" {
+		control.attr("value", value)
+	}
+	placeholder(control, text)
+	h.write("<input", control.String(), ">")
+}
+
+func (h *htmlWriter) inputToggle(n *InputToggle, control *htmlBox) {
+	on, off := firstNonEmpty(n.ValueOn, "true"), firstNonEmpty(n.ValueOff, "false")
+	control.attr("type", "checkbox")
+	control.attr("data-ac-value-on", on)
+	control.attr("data-ac-value-off", off)
+	if n.Value == on {
+		control.flag("checked")
+	}
+	h.write("<label><input", control.String(), "> ", html.EscapeString(n.Title), "</label>")
+}
+
+func (h *htmlWriter) inputChoiceSet(n *InputChoiceSet, control *htmlBox, controlID string) {
+	multi := isTrue(n.IsMultiSelect)
+	selected := map[string]bool{}
+	for _, v := range strings.Split(n.Value, ",") {
+		selected[strings.TrimSpace(v)] = true
+	}
+	switch {
+	case strings.EqualFold(string(n.Style), string(ChoiceInputStyleExpanded)):
+		typ, role := "radio", "radiogroup"
+		if multi {
+			typ, role = "checkbox", "group"
+		}
+		group := &htmlBox{}
+		group.attr("id", controlID)
+		group.attr("role", role)
+		group.attr("data-ac-input", n.ID)
+		h.write("<div", group.String(), ">")
+		for _, c := range n.Choices {
+			option := &htmlBox{}
+			option.attr("type", typ)
+			option.attr("name", n.ID)
+			option.attr("value", c.Value)
+			if selected[c.Value] {
+				option.flag("checked")
+			}
+			h.write("<label style=\"display:block\"><input", option.String(), "> ", html.EscapeString(c.Title), "</label>")
+		}
+		h.write("</div>")
+	case strings.EqualFold(string(n.Style), string(ChoiceInputStyleFiltered)) && !multi:
+		listID := controlID + "-list"
+		control.attr("list", listID)
+		placeholder(control, n.Placeholder)
+		for _, c := range n.Choices {
+			if selected[c.Value] {
+				control.attr("value", c.Value)
+			}
+		}
+		h.write("<input", control.String(), `><datalist id="`, html.EscapeString(listID), `">`)
+		for _, c := range n.Choices {
+			option := &htmlBox{}
+			option.attr("value", c.Value)
+			h.write("<option", option.String(), ">", html.EscapeString(c.Title), "</option>")
+		}
+		h.write("</datalist>")
+	default:
+		if multi {
+			control.flag("multiple")
+		}
+		h.write("<select", control.String(), ">")
+		if !multi && n.Placeholder != "" {
+			h.write(`<option value="" disabled`)
+			if n.Value == "" {
+				h.write(" selected")
+			}
+			h.write(">", html.EscapeString(n.Placeholder), "</option>")
+		}
+		for _, c := range n.Choices {
+			option := &htmlBox{}
+			option.attr("value", c.Value)
+			if selected[c.Value] {
+				option.flag("selected")
+			}
+			h.write("<option", option.String(), ">", html.EscapeString(c.Title), "</option>")
+		}
+		h.write("</select>")
+	}
+}
+
+// actions renders action buttons followed by cards of ShowCard actions
+func (h *htmlWriter) actions(actions []Node, box *htmlBox) bool {
+	if !h.hc.SupportsInteractivity {
+		return false
+	}
+	cfg := h.hc.Actions
+	if cfg.MaxActions > 0 && len(actions) > cfg.MaxActions {
+		actions = actions[:cfg.MaxActions]
+	}
+	direction := "row"
+	if strings.EqualFold(cfg.ActionsOrientation, ActionsOrientationVertical) {
+		direction = "column"
+	}
+	h.write(`<div class="ac-actions"`, box.String(), ">")
+	set := &htmlBox{}
+	set.style("display:flex;flex-wrap:wrap;flex-direction:%s;gap:%dpx", direction, cfg.ButtonSpacing)
+	if strings.EqualFold(cfg.ActionAlignment, "stretch") {
+		set.attr("data-ac-stretch", "true")
+	} else if align := cssFlexAlign(HorizontalAlignment(cfg.ActionAlignment)); align != "" {
+		set.style("justify-content:%s", align)
+	}
+	h.open("div", "ac-actionset", set)
+	var cards []*ActionShowCard
+	var cardIDs []string
+	for _, a := range actions {
+		sc, id := h.button(a)
+		if sc != nil {
+			cards = append(cards, sc)
+			cardIDs = append(cardIDs, id)
+		}
+	}
+	h.write("</div>")
+	for i, sc := range cards {
+		h.showCard(sc, cardIDs[i])
+	}
+	h.write("</div>")
+	return true
+}
+
+// button renders action button, for ShowCard it returns the action and id of its card
+func (h *htmlWriter) button(a Node) (*ActionShowCard, string) {
+	if isNilNode(a) {
+		return nil, ""
+	}
+	box := &htmlBox{}
+	title := fieldString(a, "Title")
+	if style, _ := fieldValue(a, "Style").(ActionStyle); style != "" && !strings.EqualFold(string(style), string(ActionStyleDefault)) {
+		box.attr("data-ac-style", strings.ToLower(string(style)))
+	}
+	if title != "" {
+		box.attr("title", title)
+	}
+	icon := ""
+	if url := fieldString(a, "IconURL"); url != "" {
+		i := &htmlBox{}
+		i.urlAttr("src", url)
+		i.attr("alt", "")
+		i.style("width:%dpx;height:%dpx", h.hc.Actions.IconSize, h.hc.Actions.IconSize)
+		icon = "<img" + i.String() + ">"
+	}
+	text := icon + html.EscapeString(title)
+	switch a := a.(type) {
+	case *ActionOpenURL:
+		box.urlAttr("href", a.URL)
+		box.attr("target", "_blank")
+		box.attr("rel", "noopener")
+		box.attr("role", "button")
+		h.open("a", "ac-action", box)
+		h.write(text, "</a>")
+		return nil, ""
+	case *ActionShowCard:
+		id := h.nextID("card")
+		box.attr("type", "button")
+		box.attr("data-ac-action", "showCard")
+		box.attr("aria-controls", id)
+		box.attr("aria-expanded", "false")
+		h.open("button", "ac-action", box)
+		h.write(text, "</button>")
+		return a, id
+	case *ActionSubmit, *ActionToggleVisibility:
+		box.attr("type", "button")
+		h.actionAttrs(box, a)
+		h.open("button", "ac-action", box)
+		h.write(text, "</button>")
+		return nil, ""
+	}
+	if f := nodeFallback(a); f != nil && f.Element != nil {
+		return h.button(f.Element)
+	}
+	h.write("<!-- unsupported action ", html.EscapeString(a.NodeType()), " -->")
+	return nil, ""
+}
+
+// actionAttrs adds attributes handled by the script
+func (h *htmlWriter) actionAttrs(box *htmlBox, a Node) bool {
+	switch a := a.(type) {
+	case *ActionOpenURL:
+		box.attr("data-ac-action", "openUrl")
+		box.urlAttr("data-ac-url", a.URL)
+	case *ActionSubmit:
+		box.attr("data-ac-action", "submit")
+		if len(a.Data) > 0 {
+			data, _ := json.Marshal(a.Data)
+			box.attr("data-ac-data", string(data))
+		}
+		if strings.EqualFold(a.AssociatedInputs, "none") {
+			box.attr("data-ac-inputs", "none")
+		}
+	case *ActionToggleVisibility:
+		box.attr("data-ac-action", "toggleVisibility")
+		targets, _ := json.Marshal(a.TargetElements)
+		box.attr("data-ac-targets", string(targets))
+	default:
+		return false
+	}
+	return true
+}
+
+// selectAction makes the element clickable
+func (h *htmlWriter) selectAction(box *htmlBox, a Node) {
+	if !h.hc.SupportsInteractivity || isNilNode(a) {
+		return
+	}
+	if h.actionAttrs(box, a) {
+		box.attr("role", "button")
+		box.attr("tabindex", "0")
+		if title := fieldString(a, "Title"); title != "" {
+			box.attr("aria-label", title)
+		}
+	}
+}
+
+func (h *htmlWriter) showCard(a *ActionShowCard, id string) {
+	cfg := h.hc.Actions.ShowCard
+	box := &htmlBox{}
+	box.attr("id", id)
+	box.flag("hidden")
+	box.style("display:flex;flex-direction:column;margin-top:%dpx", cfg.InlineTopMargin)
+	prev := h.styled(box, cfg.Style, false)
+	defer func() { h.style = prev }()
+	if a.Card.MinHeight != "" {
+		box.style("min-height:%s", a.Card.MinHeight)
+	}
+	h.background(box, a.Card.BackgroundImage)
+	h.open("div", "ac-showcard", box)
+	h.body(a.Card.Body, a.Card.Actions)
+	h.write("</div>")
+}
+
+func (h *htmlWriter) background(box *htmlBox, img *BackgroundImage) {
+	if img == nil || !isSafeURL(img.URL) {
+		return
+	}
+	url := strings.NewReplacer("'", "%27", `\`, "%5C", "\n", "%0A", "\r", "%0D").Replace(strings.TrimSpace(img.URL))
+	box.style("background-image:url('%s')", url)
+	switch strings.ToLower(string(img.FillMode)) {
+	case "repeat":
+		box.style("background-repeat:repeat")
+	case "repeathorizontally":
+		box.style("background-repeat:repeat-x")
+	case "repeatvertically":
+		box.style("background-repeat:repeat-y")
+	default:
+		box.style("background-size:cover;background-repeat:no-repeat")
+	}
+	x := firstNonEmpty(strings.ToLower(string(img.HorizontalAlignment)), "left")
+	y := firstNonEmpty(strings.ToLower(string(img.VerticalAlignment)), "top")
+	box.style("background-position:%s %s", x, y)
+}
+
+func (h *htmlWriter) verticalContent(box *htmlBox, align VerticalAlignment) {
+	switch strings.ToLower(string(align)) {
+	case "center":
+		box.style("justify-content:center")
+	case "bottom":
+		box.style("justify-content:flex-end")
+	}
+}
+
+// css returns stylesheet of the card scoped by its id
+func (h *htmlWriter) css() string {
+	accent := cssColor(h.hc.ForegroundColor(ContainerStyleDefault, ColorAccent, false))
+	attention := cssColor(h.hc.ForegroundColor(ContainerStyleDefault, ColorAttention, false))
+	light := cssColor(h.hc.ForegroundColor(ContainerStyleDefault, ColorLight, false))
+	bg := cssColor(h.hc.ContainerStyleConfig(ContainerStyleDefault).BackgroundColor)
+	rules := []string{
+		"#{id}{box-sizing:border-box;display:flex;flex-direction:column;overflow:hidden}",
+		"#{id} *{box-sizing:border-box}",
+		"#{id} [hidden]{display:none!important}",
+		"#{id} p{margin:0}",
+		"#{id} ul,#{id} ol{margin:0;padding-left:20px}",
+		"#{id} [data-ac-action]{cursor:pointer}",
+		"#{id} .ac-factset{border-collapse:collapse}",
+		"#{id} .ac-factset th,#{id} .ac-factset td{padding:0;text-align:left;vertical-align:top}",
+		"#{id} .ac-input input:not([type=checkbox]):not([type=radio]),#{id} .ac-input select,#{id} .ac-input textarea{width:100%;font:inherit;padding:4px 6px}",
+		"#{id} .ac-action{display:inline-flex;align-items:center;justify-content:center;gap:4px;padding:6px 12px;border:1px solid " + accent +
+			";border-radius:4px;background:" + bg + ";color:" + accent + ";font:inherit;text-decoration:none;cursor:pointer}",
+		"#{id} [data-ac-stretch] .ac-action{flex:1 1 0}",
+		"#{id} .ac-action[data-ac-style=positive]{background:" + accent + ";color:" + light + "}",
+		"#{id} .ac-action[data-ac-style=destructive]{border-color:" + attention + ";color:" + attention + "}",
+		"#{id} .ac-action[aria-expanded=true]{font-weight:" + strconv.Itoa(h.hc.FontWeight(FontTypeDefault, WeightBolder)) + "}",
+	}
+	return strings.ReplaceAll(strings.Join(rules, ""), "{id}", h.prefix)
+}
+
+// htmlScript handles actions of the card preceding the script
+const htmlScript = `(function(card){` +
+	`function values(){var data={};card.querySelectorAll("[data-ac-input]").forEach(function(el){var id=el.getAttribute("data-ac-input");` +
+	`if(el.hasAttribute("data-ac-value-on")){data[id]=el.checked?el.getAttribute("data-ac-value-on"):el.getAttribute("data-ac-value-off");}` +
+	`else if(el.tagName==="SELECT"&&el.multiple){data[id]=Array.prototype.filter.call(el.options,function(o){return o.selected;}).map(function(o){return o.value;}).join(",");}` +
+	`else if(el.tagName==="DIV"){data[id]=Array.prototype.filter.call(el.querySelectorAll("input"),function(i){return i.checked;}).map(function(i){return i.value;}).join(",");}` +
+	`else{data[id]=el.value;}});return data;}` +
+	`function valid(){var ok=true;card.querySelectorAll(".ac-input").forEach(function(w){var c=w.querySelector("input,select,textarea");var v=!c||c.checkValidity();` +
+	`var err=w.querySelector(".ac-input-error");if(err){err.hidden=v;}ok=ok&&v;});return ok;}` +
+	`card.addEventListener("click",function(e){var el=e.target.closest("[data-ac-action]");if(!el||!card.contains(el)){return;}` +
+	`var action=el.getAttribute("data-ac-action");` +
+	`if(action==="openUrl"){window.open(el.getAttribute("data-ac-url"),"_blank","noopener");}` +
+	`else if(action==="showCard"){var sc=document.getElementById(el.getAttribute("aria-controls"));sc.hidden=!sc.hidden;el.setAttribute("aria-expanded",String(!sc.hidden));}` +
+	`else if(action==="toggleVisibility"){JSON.parse(el.getAttribute("data-ac-targets")).forEach(function(t){` +
+	`card.querySelectorAll('[data-ac-id="'+CSS.escape(t.elementId)+'"]').forEach(function(n){n.hidden=typeof t.isVisible==="boolean"?!t.isVisible:!n.hidden;});});}` +
+	`else if(action==="submit"){var inputs=el.getAttribute("data-ac-inputs")!=="none";if(inputs&&!valid()){return;}` +
+	`var data=JSON.parse(el.getAttribute("data-ac-data")||"{}");if(inputs){var v=values();for(var k in v){data[k]=v[k];}}` +
+	`card.dispatchEvent(new CustomEvent("ac-submit",{bubbles:true,detail:data}));}});` +
+	`})(document.currentScript.previousElementSibling);`
+
+// cssColor converts host config color "#RRGGBB" or "#AARRGGBB" to CSS, other values are dropped
+func cssColor(c string) string {
+	if (len(c) != 7 && len(c) != 9) || c[0] != '#' {
+		return ""
+	}
+	v, err := strconv.ParseUint(c[1:], 16, 32)
+	if err != nil {
+		return ""
+	}
+	if len(c) == 7 {
+		return c
+	}
+	a, r, g, b := v>>24, (v>>16)&0xff, (v>>8)&0xff, v&0xff
+	return fmt.Sprintf("rgba(%d,%d,%d,%s)", r, g, b, strconv.FormatFloat(float64(a)/255, 'f', 2, 64))
+}
+
+func cssTextAlign(a HorizontalAlignment) string {
+	switch strings.ToLower(string(a)) {
+	case "center":
+		return "center"
+	case "right":
+		return "right"
+	case "left":
+		return "left"
+	}
+	return ""
+}
+
+func cssFlexAlign(a HorizontalAlignment) string {
+	switch strings.ToLower(string(a)) {
+	case "center":
+		return "center"
+	case "right":
+		return "flex-end"
+	case "left":
+		return "flex-start"
+	}
+	return ""
+}
+
+func placeholder(box *htmlBox, text string) {
+	if text != "" {
+		box.attr("placeholder", text)
+	}
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
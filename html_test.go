@@ -0,0 +1,173 @@
+package cards
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHTML(t *testing.T) {
+	for _, name := range []string{
+		"actionSet",
+		"background",
+		"example",
+		"fallback",
+		"images",
+		"inputs",
+		"media",
+		"rich",
+		"toggle",
+	} {
+		c, err := Parse(strings.NewReader(mustReadFile("./test/" + name + ".json")))
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		got, err := RenderHTML(c, nil)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		expected := mustReadFile("./test/html/" + name + ".html")
+		if got != expected {
+			t.Errorf("%s: expected:\n%s\nbut got:\n%s", name, expected, got)
+		}
+	}
+}
+
+func TestRenderHTMLLayout(t *testing.T) {
+	hc, err := BundledHostConfig(HostTeamsDark)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := New([]Node{
+		&TextBlock{Text: "Weekly **report**", Size: SizeLarge, Weight: WeightBolder, Wrap: TruePtr()},
+		&TextBlock{Text: "- first\n- [second](https://adaptivecards.io)\n\n1. one\n2. _two_", Wrap: TruePtr()},
+		&ColumnSet{Columns: []*Column{
+			{Width: ColumnWidthAuto, Items: []Node{&TextBlock{Text: "auto"}}},
+			{Width: ColumnWidthStretch, Items: []Node{&TextBlock{Text: "stretch"}}},
+			{Width: "2", Items: []Node{&TextBlock{Text: "weighted"}}, Separator: TruePtr()},
+			{Width: "80px", Items: []Node{&TextBlock{Text: "pixels"}}, Style: ContainerStyleGood, Bleed: TruePtr()},
+		}},
+		&Container{
+			Style:        ContainerStyleEmphasis,
+			Bleed:        TruePtr(),
+			Items:        []Node{&TextBlock{Text: "details", ID: "details", IsVisible: FalsePtr(), Color: ColorAccent, IsSubtle: TruePtr()}},
+			SelectAction: &ActionOpenURL{URL: "https://adaptivecards.io", Title: "Open"},
+		},
+		&ImageSet{Images: []*Image{
+			{URL: "https://adaptivecards.io/content/cats/1.png", AltText: "cat"},
+			{URL: "https://adaptivecards.io/content/cats/2.png", AltText: "another cat"},
+		}},
+		&InputText{ID: "name", Label: "Name", IsRequired: TruePtr(), ErrorMessage: "Name is required",
+			InlineAction: &ActionSubmit{Title: "Send"}},
+		&InputChoiceSet{ID: "color", Style: ChoiceInputStyleFiltered, Choices: []*InputChoice{
+			{Title: "Red", Value: "red"}, {Title: "Blue", Value: "blue"},
+		}},
+	}, []Node{
+		&ActionToggleVisibility{Title: "Details", TargetElements: []TargetElement{{ElementID: "details"}}},
+		&ActionSubmit{Title: "Delete", Style: ActionStyleDestructive, Data: map[string]interface{}{"action": "delete"}},
+	})
+	r := &HTMLRenderer{Config: hc, IDPrefix: "report"}
+	got, err := r.Render(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := mustReadFile("./test/html/layout.html")
+	if got != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+}
+
+func TestRenderHTMLWithoutInteractivity(t *testing.T) {
+	hc := DefaultHostConfig()
+	hc.SupportsInteractivity = false
+	c := New([]Node{
+		&TextBlock{Text: "foo"},
+		&InputText{ID: "name"},
+	}, []Node{&ActionSubmit{Title: "Submit"}})
+	got, err := RenderHTML(c, hc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "<input") || strings.Contains(got, "<button") {
+		t.Errorf("expected no inputs and actions, got:\n%s", got)
+	}
+}
+
+func TestRenderHTMLUnsafeURLs(t *testing.T) {
+	const js = "javascript:alert(1)"
+	c := New([]Node{
+		&Container{
+			Items:           []Node{&Image{URL: js, SelectAction: &ActionOpenURL{URL: js}}},
+			BackgroundImage: &BackgroundImage{URL: js},
+		},
+		&ImageSet{Images: []*Image{{URL: " JavaScript:alert(1)"}}},
+		&Media{Poster: js, Sources: []*MediaSource{{URL: js, MimeType: "video/mp4"}}},
+		&TextBlock{Text: "[link](javascript:alert(1))"},
+	}, []Node{
+		&ActionOpenURL{Title: "Open", URL: js, IconURL: "vbscript:msgbox(1)"},
+		&ActionOpenURL{Title: "Safe", URL: "https://example.com/?q='x'"},
+	})
+	got, err := RenderHTML(c, DefaultHostConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []string{"javascript:", "JavaScript:", "vbscript:"} {
+		if strings.Contains(got, bad) {
+			t.Errorf("expected %q to be dropped, got:\n%s", bad, got)
+		}
+	}
+	if !strings.Contains(got, `href="https://example.com/?q=&#39;x&#39;"`) {
+		t.Errorf("expected safe url to be kept, got:\n%s", got)
+	}
+}
+
+func TestRenderHTMLUnsafeCSS(t *testing.T) {
+	c := New([]Node{
+		&Image{URL: "https://example.com/a.png", BackgroundColor: "red;background-image:url(javascript:alert(1))"},
+		&Image{URL: "https://example.com/b.png", BackgroundColor: "#FF0000"},
+	}, nil)
+	got, err := RenderHTML(c, DefaultHostConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "javascript:") || strings.Contains(got, "background-color:red") {
+		t.Errorf("expected invalid color to be dropped, got:\n%s", got)
+	}
+	if !strings.Contains(got, "background-color:#FF0000") {
+		t.Errorf("expected valid color to be kept, got:\n%s", got)
+	}
+	r := &HTMLRenderer{IDPrefix: "x{}</style><script>"}
+	if _, err := r.Render(c); err == nil {
+		t.Error("expected error for invalid id prefix")
+	}
+}
+
+func TestMarkdownHTML(t *testing.T) {
+	cases := []struct {
+		text, expected string
+	}{
+		{"**bold** and _italic_ and *italic*", "<p><strong>bold</strong> and <em>italic</em> and <em>italic</em></p>"},
+		{"snake_case_name <b>", "<p>snake_case_name &lt;b&gt;</p>"},
+		{"[link](https://example.com) [bad](javascript:void)", `<p><a href="https://example.com" target="_blank" rel="noopener">link</a> bad</p>`},
+		{"line 1\nline 2\n\n- a\n- b", "<p>line 1<br>line 2</p><ul><li>a</li><li>b</li></ul>"},
+		{"3. three\n4. four", `<ol start="3"><li>three</li><li>four</li></ol>`},
+	}
+	for _, c := range cases {
+		if got := markdownHTML(c.text); got != c.expected {
+			t.Errorf("%q: expected %q, got %q", c.text, c.expected, got)
+		}
+	}
+}
+
+func TestCSSColor(t *testing.T) {
+	if got := cssColor("#80FF0000"); got != "rgba(255,0,0,0.50)" {
+		t.Errorf("expected rgba color, got %s", got)
+	}
+	if got := cssColor("#FF0000"); got != "#FF0000" {
+		t.Errorf("expected color to be kept, got %s", got)
+	}
+	for _, c := range []string{"red", "#FF00", "#GG0000", "#FF0000;x:y", "url(x)"} {
+		if got := cssColor(c); got != "" {
+			t.Errorf("%q: expected color to be dropped, got %s", c, got)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package cards
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Reference error codes
+const (
+	// CodeDuplicateID is used when several elements have the same id
+	CodeDuplicateID = "duplicate_id"
+	// CodeUnknownTarget is used when Action.ToggleVisibility targets element which doesn't exist
+	CodeUnknownTarget = "unknown_target"
+	// CodeDataCollision is used when input id is the same as a key of Action.Submit data
+	CodeDataCollision = "data_collision"
+)
+
+// IDIndex returns paths of the card elements by id in document order,
+// elements of Action.ShowCard cards and fallbacks are included
+func (c *Card) IDIndex() map[string][]Path {
+	index := map[string][]Path{}
+	walkTree(c, "", func(path string, n Node) {
+		if id := nodeID(n); id != "" {
+			index[id] = append(index[id], Path(path))
+		}
+	})
+	return index
+}
+
+// ValidateReferences reports elements sharing an id, Action.ToggleVisibility targets which don't exist,
+// invalid associatedInputs of Action.Submit and input ids which overwrite keys of Action.Submit data
+// in the submitted payload. An element and its fallback (or their children) can share ids
+// because only one of them is rendered.
+func (c *Card) ValidateReferences() ValidationErrors {
+	var errs ValidationErrors
+	index := c.IDIndex()
+
+	ids := make([]string, 0, len(index))
+	for id := range index {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	duplicates := map[Path]Path{}
+	for _, id := range ids {
+		paths := index[id]
+		for i, path := range paths {
+			for _, prev := range paths[:i] {
+				if !alternativePaths(prev, path) {
+					duplicates[path] = prev
+					break
+				}
+			}
+		}
+	}
+
+	var walk func(parent interface{}, path string, scopes []Node)
+	walk = func(parent interface{}, path string, scopes []Node) {
+		for _, child := range childNodes(parent) {
+			n := child.node
+			childPath := path + "/" + child.path
+			if isNilNode(n) {
+				continue
+			}
+			r := &reporter{errs: &errs, path: childPath, typ: n.NodeType(), id: nodeID(n)}
+			if prev, ok := duplicates[Path(childPath)]; ok {
+				r.add(CodeDuplicateID, fmt.Sprintf("id %q is already used at %s", nodeID(n), prev))
+			}
+			switch n := n.(type) {
+			case *ActionToggleVisibility:
+				for i, t := range n.TargetElements {
+					if t.ElementID != "" && len(index[t.ElementID]) == 0 {
+						r.at("targetElements/%d", i).add(CodeUnknownTarget,
+							fmt.Sprintf("target element %q doesn't exist", t.ElementID))
+					}
+				}
+			case *ActionSubmit:
+				checkSubmitReferences(r, n, scopeInputs(c, scopes))
+			}
+			if sc, ok := n.(*ActionShowCard); ok {
+				walk(sc, childPath, append(scopes[:len(scopes):len(scopes)], sc))
+				continue
+			}
+			walk(n, childPath, scopes)
+		}
+	}
+	walk(c, "", []Node{nil})
+	return errs
+}
+
+// checkSubmitReferences checks associatedInputs of the action and its data keys against inputs it submits
+func checkSubmitReferences(r *reporter, a *ActionSubmit, inputs []Node) {
+	switch strings.ToLower(a.AssociatedInputs) {
+	case "", "auto":
+	case "none":
+		return
+	default:
+		r.at("associatedInputs").add(CodeInvalidValue,
+			fmt.Sprintf("associatedInputs must be auto or none, got %q", a.AssociatedInputs))
+		return
+	}
+	reported := map[string]bool{}
+	for _, n := range inputs {
+		id := nodeID(n)
+		if _, ok := a.Data[id]; ok && !reported[id] {
+			reported[id] = true
+			r.at("data").add(CodeDataCollision,
+				fmt.Sprintf("data key %q is overwritten by the value of input %q", id, id))
+		}
+	}
+}
+
+// alternativePaths tells if elements at the paths are never rendered together:
+// one of them is in a fallback of an element and the other is that element or its child
+func alternativePaths(a, b Path) bool {
+	return inFallbackOf(a, b) || inFallbackOf(b, a)
+}
+
+// inFallbackOf tells if a is in fallback of an element which is b or holds b outside of its fallback
+func inFallbackOf(a, b Path) bool {
+	s := string(a)
+	for i := strings.Index(s, "/fallback"); i >= 0; {
+		end := i + len("/fallback")
+		if end == len(s) || s[end] == '/' {
+			owner := s[:i]
+			inOwner := string(b) == owner || strings.HasPrefix(string(b), owner+"/")
+			if inOwner && !strings.HasPrefix(string(b)+"/", owner+"/fallback/") {
+				return true
+			}
+		}
+		next := strings.Index(s[end:], "/fallback")
+		if next < 0 {
+			break
+		}
+		i = end + next
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package cards
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIDIndex(t *testing.T) {
+	c := New([]Node{
+		&TextBlock{ID: "title", Text: "a"},
+		&Media{ID: "video", Fallback: FallbackElement(&Image{ID: "video", URL: "a.png"})},
+	}, []Node{
+		&ActionShowCard{ID: "more", Title: "More", Card: NestedCard{
+			Body: []Node{&InputText{ID: "title"}},
+		}},
+	})
+	expected := map[string][]Path{
+		"title": {"/body/0", "/actions/0/card/body/0"},
+		"video": {"/body/1", "/body/1/fallback"},
+		"more":  {"/actions/0"},
+	}
+	if got := c.IDIndex(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v but got %v", expected, got)
+	}
+}
+
+func TestValidateReferences(t *testing.T) {
+	c := New([]Node{
+		&InputText{ID: "name"},
+		&Container{ID: "box", Items: []Node{
+			&InputText{ID: "name"},
+			&ActionSet{Actions: []Node{
+				&ActionToggleVisibility{Title: "Toggle", TargetElements: []TargetElement{
+					{ElementID: "box"}, {ElementID: "missing"},
+				}},
+			}},
+		}},
+		// fallback may share ids with the element it replaces
+		&ColumnSet{ID: "cols", Columns: []*Column{{Items: []Node{&InputDate{ID: "due"}}}},
+			Fallback: FallbackElement(&Container{ID: "cols", Items: []Node{&InputDate{ID: "due"}}})},
+	}, []Node{
+		&ActionSubmit{Title: "Send", Data: map[string]interface{}{"name": "x", "action": "send"}},
+		&ActionSubmit{Title: "Skip", AssociatedInputs: "None", Data: map[string]interface{}{"name": "x"}},
+		&ActionSubmit{Title: "Bad", AssociatedInputs: "all"},
+		&ActionShowCard{Title: "More", Card: NestedCard{
+			Body: []Node{&InputText{ID: "comment"}},
+			Actions: []Node{
+				&ActionSubmit{Title: "Comment", Data: map[string]interface{}{"comment": "", "due": ""}},
+			},
+		}},
+	})
+	type problem struct{ path, code string }
+	var got []problem
+	for _, e := range c.ValidateReferences() {
+		got = append(got, problem{e.Path, e.Code})
+	}
+	expected := []problem{
+		{"/body/1/items/0", CodeDuplicateID},
+		{"/body/1/items/1/actions/0/targetElements/1", CodeUnknownTarget},
+		{"/actions/0/data", CodeDataCollision},
+		{"/actions/2/associatedInputs", CodeInvalidValue},
+		{"/actions/3/card/actions/0/data", CodeDataCollision},
+		{"/actions/3/card/actions/0/data", CodeDataCollision},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v but got %v", expected, got)
+	}
+	if errs := c.ValidateReferences(); errs[0].Message != `id "name" is already used at /body/0` {
+		t.Errorf("unexpected message %q", errs[0].Message)
+	}
+
+	valid := New([]Node{&InputText{ID: "name"}}, []Node{&ActionSubmit{Title: "Send", Data: map[string]interface{}{"action": "send"}}})
+	if errs := valid.ValidateReferences(); len(errs) != 0 {
+		t.Errorf("expected no errors but got %v", errs)
+	}
+}
+
+func TestAlternativePaths(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     Path
+		expected bool
+	}{
+		{"/body/0", "/body/0/fallback", true},
+		{"/body/0/items/1", "/body/0/fallback/items/1", true},
+		{"/body/0/fallback/fallback", "/body/0/fallback", true},
+		{"/body/0/fallback/items/0", "/body/0/fallback/items/1", false},
+		{"/body/0", "/body/1/fallback", false},
+		{"/body/0/fallbackish", "/body/0", false},
+	} {
+		if got := alternativePaths(tc.a, tc.b); got != tc.expected {
+			t.Errorf("expected %v for %s and %s", tc.expected, tc.a, tc.b)
+		}
+	}
+}
@@ -1,65 +1,83 @@
 package cards
 
-import (
-	"errors"
-)
-
 // InputText is Input.Text type
 type InputText struct {
-	Type         string `json:"type"` // required
-	ID           string `json:"id"`   // required
-	IsMultiline  *bool  `json:"isMultiline,omitempty"`
-	MaxLength    int64  `json:"maxLength,omitempty"`
-	Placeholder  string `json:"placeholder,omitempty"`
-	Regex        string `json:"regex,omitempty"`
-	Style        string `json:"style,omitempty"`
-	InlineAction Node   `json:"inlineAction,omitempty"` // FIXME
-	Value        string `json:"value,omitempty"`
+	Type         string         `json:"type"` // required
+	ID           string         `json:"id"`   // required
+	IsMultiline  *bool          `json:"isMultiline,omitempty"`
+	MaxLength    int64          `json:"maxLength,omitempty"`
+	Placeholder  string         `json:"placeholder,omitempty"`
+	Regex        string         `json:"regex,omitempty"`
+	Style        TextInputStyle `json:"style,omitempty"`
+	InlineAction Node           `json:"inlineAction,omitempty"` // FIXME
+	Value        string         `json:"value,omitempty"`
 	// inherited
-	ErrorMessage string            `json:"errorMessage,omitempty"`
-	IsRequired   *bool             `json:"isRequired,omitempty"`
-	Label        string            `json:"label,omitempty"`
-	Height       string            `json:"height,omitempty"`
-	Separator    *bool             `json:"separator,omitempty"`
-	Spacing      string            `json:"spacing,omitempty"`
-	IsVisible    *bool             `json:"isVisible,omitempty"`
-	Requires     map[string]string `json:"requires,omitempty"`
+	ErrorMessage string             `json:"errorMessage,omitempty"`
+	IsRequired   *bool              `json:"isRequired,omitempty"`
+	Label        string             `json:"label,omitempty"`
+	Fallback     *Fallback          `json:"fallback,omitempty"`
+	Height       BlockElementHeight `json:"height,omitempty"`
+	Separator    *bool              `json:"separator,omitempty"`
+	Spacing      Spacing            `json:"spacing,omitempty"`
+	IsVisible    *bool              `json:"isVisible,omitempty"`
+	Requires     map[string]string  `json:"requires,omitempty"`
+}
+
+// NodeType returns InputText element type
+func (n *InputText) NodeType() string {
+	return InputTextType
 }
 
-func (n *InputText) prepare() error {
+// Prepare sets InputText type and validates its fields
+func (n *InputText) Prepare() error {
 	n.Type = InputTextType
+	return check(n)
+}
+
+func (n *InputText) check(r *reporter) {
 	if n.ID == "" {
-		return errors.New("InputText id is required")
+		r.required("InputText id is required")
 	}
-	return nil
+	r.enum("style", n.Style)
+	r.layout(n.Height, n.Spacing)
 }
 
 // InputNumber allows a user to enter a number.
 type InputNumber struct {
-	Type        string  `json:"type"` // required
-	ID          string  `json:"id"`   // required
-	Max         float64 `json:"max,omitempty"`
-	Min         float64 `json:"min,omitempty"`
-	Placeholder string  `json:"placeholder,omitempty"`
-	Value       float64 `json:"value,omitempty"`
+	Type        string   `json:"type"` // required
+	ID          string   `json:"id"`   // required
+	Max         *float64 `json:"max,omitempty"`
+	Min         *float64 `json:"min,omitempty"`
+	Placeholder string   `json:"placeholder,omitempty"`
+	Value       float64  `json:"value,omitempty"`
 	// inherited
-	ErrorMessage string            `json:"errorMessage,omitempty"`
-	IsRequired   *bool             `json:"isRequired,omitempty"`
-	Label        string            `json:"label,omitempty"`
-	Fallback     []Node            `json:"fallback,omitempty"`
-	Height       string            `json:"height,omitempty"`
-	Separator    *bool             `json:"separator,omitempty"`
-	Spacing      string            `json:"spacing,omitempty"`
-	IsVisible    *bool             `json:"isVisible,omitempty"`
-	Requires     map[string]string `json:"requires,omitempty"`
-}
-
-func (n *InputNumber) prepare() error {
+	ErrorMessage string             `json:"errorMessage,omitempty"`
+	IsRequired   *bool              `json:"isRequired,omitempty"`
+	Label        string             `json:"label,omitempty"`
+	Fallback     *Fallback          `json:"fallback,omitempty"`
+	Height       BlockElementHeight `json:"height,omitempty"`
+	Separator    *bool              `json:"separator,omitempty"`
+	Spacing      Spacing            `json:"spacing,omitempty"`
+	IsVisible    *bool              `json:"isVisible,omitempty"`
+	Requires     map[string]string  `json:"requires,omitempty"`
+}
+
+// NodeType returns InputNumber element type
+func (n *InputNumber) NodeType() string {
+	return InputNumberType
+}
+
+// Prepare sets InputNumber type and validates its fields
+func (n *InputNumber) Prepare() error {
 	n.Type = InputNumberType
+	return check(n)
+}
+
+func (n *InputNumber) check(r *reporter) {
 	if n.ID == "" {
-		return errors.New("InputNumber id is required")
+		r.required("InputNumber id is required")
 	}
-	return nil
+	r.layout(n.Height, n.Spacing)
 }
 
 // InputTime lets a user select a time.
@@ -71,23 +89,33 @@ type InputTime struct {
 	Placeholder string `json:"placeholder,omitempty"`
 	Value       string `json:"value,omitempty"`
 	// inherited
-	ErrorMessage string            `json:"errorMessage,omitempty"`
-	IsRequired   *bool             `json:"isRequired,omitempty"`
-	Label        string            `json:"label,omitempty"`
-	Fallback     []Node            `json:"fallback,omitempty"`
-	Height       string            `json:"height,omitempty"`
-	Separator    *bool             `json:"separator,omitempty"`
-	Spacing      string            `json:"spacing,omitempty"`
-	IsVisible    *bool             `json:"isVisible,omitempty"`
-	Requires     map[string]string `json:"requires,omitempty"`
-}
-
-func (n *InputTime) prepare() error {
+	ErrorMessage string             `json:"errorMessage,omitempty"`
+	IsRequired   *bool              `json:"isRequired,omitempty"`
+	Label        string             `json:"label,omitempty"`
+	Fallback     *Fallback          `json:"fallback,omitempty"`
+	Height       BlockElementHeight `json:"height,omitempty"`
+	Separator    *bool              `json:"separator,omitempty"`
+	Spacing      Spacing            `json:"spacing,omitempty"`
+	IsVisible    *bool              `json:"isVisible,omitempty"`
+	Requires     map[string]string  `json:"requires,omitempty"`
+}
+
+// NodeType returns InputTime element type
+func (n *InputTime) NodeType() string {
+	return InputTimeType
+}
+
+// Prepare sets InputTime type and validates its fields
+func (n *InputTime) Prepare() error {
 	n.Type = InputTimeType
+	return check(n)
+}
+
+func (n *InputTime) check(r *reporter) {
 	if n.ID == "" {
-		return errors.New("InputTime id is required")
+		r.required("InputTime id is required")
 	}
-	return nil
+	r.layout(n.Height, n.Spacing)
 }
 
 // InputDate lets a user choose a date.
@@ -99,61 +127,80 @@ type InputDate struct {
 	Placeholder string `json:"placeholder,omitempty"`
 	Value       string `json:"value,omitempty"`
 	// inherited
-	ErrorMessage string            `json:"errorMessage,omitempty"`
-	IsRequired   *bool             `json:"isRequired,omitempty"`
-	Label        string            `json:"label,omitempty"`
-	Fallback     []Node            `json:"fallback,omitempty"`
-	Height       string            `json:"height,omitempty"`
-	Separator    *bool             `json:"separator,omitempty"`
-	Spacing      string            `json:"spacing,omitempty"`
-	IsVisible    *bool             `json:"isVisible,omitempty"`
-	Requires     map[string]string `json:"requires,omitempty"`
-}
-
-func (n *InputDate) prepare() error {
+	ErrorMessage string             `json:"errorMessage,omitempty"`
+	IsRequired   *bool              `json:"isRequired,omitempty"`
+	Label        string             `json:"label,omitempty"`
+	Fallback     *Fallback          `json:"fallback,omitempty"`
+	Height       BlockElementHeight `json:"height,omitempty"`
+	Separator    *bool              `json:"separator,omitempty"`
+	Spacing      Spacing            `json:"spacing,omitempty"`
+	IsVisible    *bool              `json:"isVisible,omitempty"`
+	Requires     map[string]string  `json:"requires,omitempty"`
+}
+
+// NodeType returns InputDate element type
+func (n *InputDate) NodeType() string {
+	return InputDateType
+}
+
+// Prepare sets InputDate type and validates its fields
+func (n *InputDate) Prepare() error {
 	n.Type = InputDateType
+	return check(n)
+}
+
+func (n *InputDate) check(r *reporter) {
 	if n.ID == "" {
-		return errors.New("InputDate id is required")
+		r.required("InputDate id is required")
 	}
-	return nil
+	r.layout(n.Height, n.Spacing)
 }
 
 // InputChoiceSet allows a user to input a Choice.
 type InputChoiceSet struct {
-	Type          string         `json:"type"`    // required
-	Choices       []*InputChoice `json:"choices"` // required
-	ID            string         `json:"id"`      // required
-	IsMultiSelect *bool          `json:"isMultiSelect,omitempty"`
-	Style         string         `json:"style,omitempty"`
-	Placeholder   string         `json:"placeholder,omitempty"`
-	Value         string         `json:"value,omitempty"`
-	Wrap          *bool          `json:"wrap,omitempty"`
+	Type          string           `json:"type"`    // required
+	Choices       []*InputChoice   `json:"choices"` // required
+	ID            string           `json:"id"`      // required
+	IsMultiSelect *bool            `json:"isMultiSelect,omitempty"`
+	Style         ChoiceInputStyle `json:"style,omitempty"`
+	Placeholder   string           `json:"placeholder,omitempty"`
+	Value         string           `json:"value,omitempty"`
+	Wrap          *bool            `json:"wrap,omitempty"`
 	// inherited
-	ErrorMessage string            `json:"errorMessage,omitempty"`
-	IsRequired   *bool             `json:"isRequired,omitempty"`
-	Label        string            `json:"label,omitempty"`
-	Fallback     []Node            `json:"fallback,omitempty"`
-	Height       string            `json:"height,omitempty"`
-	Separator    *bool             `json:"separator,omitempty"`
-	Spacing      string            `json:"spacing,omitempty"`
-	IsVisible    *bool             `json:"isVisible,omitempty"`
-	Requires     map[string]string `json:"requires,omitempty"`
-}
-
-func (n *InputChoiceSet) prepare() error {
+	ErrorMessage string             `json:"errorMessage,omitempty"`
+	IsRequired   *bool              `json:"isRequired,omitempty"`
+	Label        string             `json:"label,omitempty"`
+	Fallback     *Fallback          `json:"fallback,omitempty"`
+	Height       BlockElementHeight `json:"height,omitempty"`
+	Separator    *bool              `json:"separator,omitempty"`
+	Spacing      Spacing            `json:"spacing,omitempty"`
+	IsVisible    *bool              `json:"isVisible,omitempty"`
+	Requires     map[string]string  `json:"requires,omitempty"`
+}
+
+// NodeType returns InputChoiceSet element type
+func (n *InputChoiceSet) NodeType() string {
+	return InputChoiceSetType
+}
+
+// Prepare sets InputChoiceSet type and validates its fields and choices
+func (n *InputChoiceSet) Prepare() error {
 	n.Type = InputChoiceSetType
+	return check(n)
+}
+
+func (n *InputChoiceSet) check(r *reporter) {
 	if n.ID == "" {
-		return errors.New("InputChoiceSet id is required")
+		r.required("InputChoiceSet id is required")
 	}
 	if len(n.Choices) < 1 {
-		return errors.New("InputChoiceSet must have choices")
+		r.required("InputChoiceSet must have choices")
 	}
-	for _, c := range n.Choices {
-		if err := c.prepare(); err != nil {
-			return err
-		}
+	r.enum("style", n.Style)
+	r.layout(n.Height, n.Spacing)
+	for i, c := range n.Choices {
+		c.check(r.at("choices/%d", i))
 	}
-	return nil
 }
 
 // InputChoice describes a choice for use in a ChoiceSet.
@@ -163,15 +210,14 @@ type InputChoice struct {
 	Value string `json:"value"` // required
 }
 
-func (c *InputChoice) prepare() error {
+func (c *InputChoice) check(r *reporter) {
 	if c.Title == "" {
-		return errors.New("Choice must have title")
+		r.required("Choice must have title")
 	}
 	if c.Value == "" {
-		return errors.New("Choice must have value")
+		r.required("Choice must have value")
 	}
 	// TODO validate no comma in the value
-	return nil
 }
 
 // InputToggle lets a user choose between two options.
@@ -184,24 +230,34 @@ type InputToggle struct {
 	ValueOn  string `json:"valueOn,omitempty"`
 	Wrap     *bool  `json:"wrap,omitempty"`
 	// inherited
-	ErrorMessage string            `json:"errorMessage,omitempty"`
-	IsRequired   *bool             `json:"isRequired,omitempty"`
-	Label        string            `json:"label,omitempty"`
-	Fallback     []Node            `json:"fallback,omitempty"`
-	Height       string            `json:"height,omitempty"`
-	Separator    *bool             `json:"separator,omitempty"`
-	Spacing      string            `json:"spacing,omitempty"`
-	IsVisible    *bool             `json:"isVisible,omitempty"`
-	Requires     map[string]string `json:"requires,omitempty"`
-}
-
-func (n *InputToggle) prepare() error {
+	ErrorMessage string             `json:"errorMessage,omitempty"`
+	IsRequired   *bool              `json:"isRequired,omitempty"`
+	Label        string             `json:"label,omitempty"`
+	Fallback     *Fallback          `json:"fallback,omitempty"`
+	Height       BlockElementHeight `json:"height,omitempty"`
+	Separator    *bool              `json:"separator,omitempty"`
+	Spacing      Spacing            `json:"spacing,omitempty"`
+	IsVisible    *bool              `json:"isVisible,omitempty"`
+	Requires     map[string]string  `json:"requires,omitempty"`
+}
+
+// NodeType returns InputToggle element type
+func (n *InputToggle) NodeType() string {
+	return InputToggleType
+}
+
+// Prepare sets InputToggle type and validates its fields
+func (n *InputToggle) Prepare() error {
 	n.Type = InputToggleType
+	return check(n)
+}
+
+func (n *InputToggle) check(r *reporter) {
 	if n.ID == "" {
-		return errors.New("InputToggle id is required")
+		r.required("InputToggle id is required")
 	}
 	if n.Title == "" {
-		return errors.New("InputToggle must have title")
+		r.required("InputToggle must have title")
 	}
-	return nil
+	r.layout(n.Height, n.Spacing)
 }
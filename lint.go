@@ -0,0 +1,419 @@
+package cards
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Severity is importance of a lint issue
+type Severity int
+
+// Severity values
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// LintIssue describes a problem the card has in a specific host
+type LintIssue struct {
+	Path     string // JSON pointer of the element, empty for the card
+	Type     string // element type
+	ID       string // element id if any
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+func (i *LintIssue) String() string {
+	msg := fmt.Sprintf("%s [%s] %s", i.Severity, i.Rule, i.Message)
+	if i.Path == "" {
+		return msg
+	}
+	return i.Path + ": " + msg
+}
+
+// LintIssues holds all issues found in the card
+type LintIssues []*LintIssue
+
+// HasErrors tells if there are issues with error severity
+func (l LintIssues) HasErrors() bool {
+	for _, i := range l {
+		if i.Severity >= SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// HostProfile describes limitations of a host which are not expressed by card schema
+type HostProfile struct {
+	Name                string
+	Config              *HostConfig         // nil means DefaultHostConfig
+	MaxVersion          string              // the newest supported card version, empty means any
+	UnsupportedElements []string            // element types the host can't render
+	NoNestedShowCard    bool                // Action.ShowCard is not supported inside a shown card
+	Severities          map[string]Severity // overrides of rule severities
+}
+
+// hostConfig returns host config of the profile
+func (p *HostProfile) hostConfig() *HostConfig {
+	if p.Config == nil {
+		return DefaultHostConfig()
+	}
+	return p.Config
+}
+
+// clone returns deep copy of the profile, so registered profiles can't be changed by callers
+func (p *HostProfile) clone() *HostProfile {
+	return deepCopy(reflect.ValueOf(p)).Interface().(*HostProfile)
+}
+
+// LintRule checks the card for a host profile.
+// Check reports issues with LintContext.Report.
+type LintRule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(ctx *LintContext)
+}
+
+// LintContext gives a rule access to the linted card and host profile
+type LintContext struct {
+	Card     *Card
+	Profile  *HostProfile
+	rule     *LintRule
+	severity Severity
+	elements []LintElement
+	issues   *LintIssues
+}
+
+// LintElement is an element of the linted card
+type LintElement struct {
+	Path      string
+	Node      Node
+	Ancestors []Node // parent elements from the outermost one, the card is not included
+}
+
+// Elements returns all elements of the card depth-first
+func (ctx *LintContext) Elements() []LintElement {
+	return ctx.elements
+}
+
+// Report adds an issue for the element at path, n is nil for card level issues
+func (ctx *LintContext) Report(path string, n Node, msg string) {
+	issue := &LintIssue{Path: path, Rule: ctx.rule.Name, Severity: ctx.severity, Message: msg}
+	if n != nil {
+		issue.Type = n.NodeType()
+		issue.ID = nodeID(n)
+	}
+	*ctx.issues = append(*ctx.issues, issue)
+}
+
+// Reportf is like Report but formats the message
+func (ctx *LintContext) Reportf(path string, n Node, format string, args ...interface{}) {
+	ctx.Report(path, n, fmt.Sprintf(format, args...))
+}
+
+// Lint profile names
+const (
+	ProfileTeams   = "teams"
+	ProfileOutlook = "outlook"
+	ProfileWebChat = "webchat"
+	ProfileWebex   = "webex"
+)
+
+var (
+	lintMu    sync.RWMutex
+	lintRules = []*LintRule{
+		{
+			Name:        "version",
+			Description: "card version is not newer than the host supports",
+			Severity:    SeverityError,
+			Check:       checkVersionRule,
+		},
+		{
+			Name:        "unsupported-element",
+			Description: "card doesn't use elements the host can't render",
+			Severity:    SeverityError,
+			Check:       checkUnsupportedElementRule,
+		},
+		{
+			Name:        "max-actions",
+			Description: "number of actions doesn't exceed host config actions.maxActions",
+			Severity:    SeverityWarning,
+			Check:       checkMaxActionsRule,
+		},
+		{
+			Name:        "nested-show-card",
+			Description: "Action.ShowCard is not used inside a shown card if the host doesn't support it",
+			Severity:    SeverityError,
+			Check:       checkNestedShowCardRule,
+		},
+		{
+			Name:        "interactivity",
+			Description: "card has no inputs and actions if the host doesn't support interactivity",
+			Severity:    SeverityError,
+			Check:       checkInteractivityRule,
+		},
+		{
+			Name:        "requires",
+			Description: "element requirements are satisfied by host capabilities",
+			Severity:    SeverityInfo,
+			Check:       checkRequiresRule,
+		},
+	}
+	hostProfiles = map[string]*HostProfile{
+		ProfileTeams: {
+			Name:                ProfileTeams,
+			Config:              mustBundledHostConfig(HostTeamsLight),
+			MaxVersion:          Version15,
+			UnsupportedElements: []string{MediaType},
+			NoNestedShowCard:    true, // Teams mobile
+		},
+		ProfileOutlook: {
+			Name:       ProfileOutlook,
+			Config:     mustBundledHostConfig(HostOutlook),
+			MaxVersion: Version14,
+		},
+		ProfileWebChat: {
+			Name:       ProfileWebChat,
+			Config:     mustBundledHostConfig(HostWebChat),
+			MaxVersion: Version15,
+		},
+		ProfileWebex: {
+			Name:                ProfileWebex,
+			Config:              mustBundledHostConfig(HostWebex),
+			MaxVersion:          Version13,
+			UnsupportedElements: []string{MediaType},
+		},
+	}
+)
+
+func mustBundledHostConfig(name string) *HostConfig {
+	hc, err := BundledHostConfig(name)
+	if err != nil {
+		panic(err)
+	}
+	return hc
+}
+
+// RegisterLintRule adds a custom rule which is run by Lint for every profile.
+// RegisterLintRule panics if the rule has no name or check or the rule is already registered.
+func RegisterLintRule(rule LintRule) {
+	if rule.Name == "" {
+		panic("cards: RegisterLintRule rule name is empty")
+	}
+	if rule.Check == nil {
+		panic("cards: RegisterLintRule check is nil for " + rule.Name)
+	}
+	lintMu.Lock()
+	defer lintMu.Unlock()
+	for _, r := range lintRules {
+		if r.Name == rule.Name {
+			panic("cards: RegisterLintRule called twice for " + rule.Name)
+		}
+	}
+	lintRules = append(lintRules, &rule)
+}
+
+// LintRules returns all registered rules
+func LintRules() []LintRule {
+	lintMu.RLock()
+	defer lintMu.RUnlock()
+	rules := make([]LintRule, 0, len(lintRules))
+	for _, r := range lintRules {
+		rules = append(rules, *r)
+	}
+	return rules
+}
+
+// RegisterHostProfile adds a copy of custom host profile.
+// RegisterHostProfile panics if the profile has no name or a profile with the same name is already registered.
+func RegisterHostProfile(p *HostProfile) {
+	if p == nil || p.Name == "" {
+		panic("cards: RegisterHostProfile profile name is empty")
+	}
+	lintMu.Lock()
+	defer lintMu.Unlock()
+	if _, ok := hostProfiles[p.Name]; ok {
+		panic("cards: RegisterHostProfile called twice for " + p.Name)
+	}
+	hostProfiles[p.Name] = p.clone()
+}
+
+// LookupHostProfile returns a copy of registered host profile
+func LookupHostProfile(name string) (*HostProfile, bool) {
+	lintMu.RLock()
+	defer lintMu.RUnlock()
+	p, ok := hostProfiles[name]
+	if !ok {
+		return nil, false
+	}
+	return p.clone(), true
+}
+
+// HostProfiles returns names of all registered host profiles
+func HostProfiles() []string {
+	lintMu.RLock()
+	defer lintMu.RUnlock()
+	names := make([]string, 0, len(hostProfiles))
+	for name := range hostProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lint runs all registered rules against the card for the host profile.
+// Nil profile runs generic rules with default severities for a host with default config and no other limitations.
+func Lint(c *Card, profile *HostProfile) LintIssues {
+	if profile == nil {
+		profile = &HostProfile{Name: "host"}
+	}
+	var issues LintIssues
+	elements := lintElements(c)
+	for _, rule := range LintRules() {
+		rule := rule
+		severity := rule.Severity
+		if s, ok := profile.Severities[rule.Name]; ok {
+			severity = s
+		}
+		rule.Check(&LintContext{
+			Card:     c,
+			Profile:  profile,
+			rule:     &rule,
+			severity: severity,
+			elements: elements,
+			issues:   &issues,
+		})
+	}
+	return issues
+}
+
+// LintFor runs Lint with registered host profile
+func LintFor(c *Card, profileName string) (LintIssues, error) {
+	p, ok := LookupHostProfile(profileName)
+	if !ok {
+		return nil, fmt.Errorf("unknown host profile %q", profileName)
+	}
+	return Lint(c, p), nil
+}
+
+func lintElements(c *Card) []LintElement {
+	var res []LintElement
+	var walk func(parent interface{}, path string, ancestors []Node)
+	walk = func(parent interface{}, path string, ancestors []Node) {
+		for _, child := range childNodes(parent) {
+			if child.node == nil {
+				continue
+			}
+			childPath := path + "/" + child.path
+			res = append(res, LintElement{Path: childPath, Node: child.node, Ancestors: ancestors})
+			next := make([]Node, len(ancestors), len(ancestors)+1)
+			copy(next, ancestors)
+			walk(child.node, childPath, append(next, child.node))
+		}
+	}
+	walk(c, "", nil)
+	return res
+}
+
+func checkVersionRule(ctx *LintContext) {
+	max := ctx.Profile.MaxVersion
+	if max == "" {
+		return
+	}
+	if isNewer(ctx.Card.Version, max) {
+		ctx.Reportf("", nil, "card version %s is not supported by %s, the newest supported version is %s",
+			ctx.Card.Version, ctx.Profile.Name, max)
+	}
+}
+
+func checkUnsupportedElementRule(ctx *LintContext) {
+	for _, e := range ctx.Elements() {
+		for _, t := range ctx.Profile.UnsupportedElements {
+			if e.Node.NodeType() != t {
+				continue
+			}
+			if f := nodeFallback(e.Node); f != nil {
+				continue // fallback is rendered instead
+			}
+			ctx.Reportf(e.Path, e.Node, "%s is not supported by %s, add fallback", t, ctx.Profile.Name)
+		}
+	}
+}
+
+func checkMaxActionsRule(ctx *LintContext) {
+	max := ctx.Profile.hostConfig().Actions.MaxActions
+	if max <= 0 {
+		return
+	}
+	report := func(path string, n Node, count int) {
+		if count > max {
+			ctx.Reportf(path, n, "%d actions are used, %s shows only %d", count, ctx.Profile.Name, max)
+		}
+	}
+	report("/actions", nil, len(ctx.Card.Actions))
+	for _, e := range ctx.Elements() {
+		switch n := e.Node.(type) {
+		case *ActionSet:
+			report(e.Path, n, len(n.Actions))
+		case *ActionShowCard:
+			report(e.Path+"/card/actions", n, len(n.Card.Actions))
+		}
+	}
+}
+
+func checkNestedShowCardRule(ctx *LintContext) {
+	if !ctx.Profile.NoNestedShowCard {
+		return
+	}
+	for _, e := range ctx.Elements() {
+		if e.Node.NodeType() != ActionShowCardType {
+			continue
+		}
+		for _, a := range e.Ancestors {
+			if a.NodeType() == ActionShowCardType {
+				ctx.Reportf(e.Path, e.Node, "Action.ShowCard inside a shown card is not supported by %s", ctx.Profile.Name)
+				break
+			}
+		}
+	}
+}
+
+func checkInteractivityRule(ctx *LintContext) {
+	if ctx.Profile.hostConfig().SupportsInteractivity {
+		return
+	}
+	for _, e := range ctx.Elements() {
+		if isAction(e.Node) || strings.HasPrefix(e.Node.NodeType(), "Input.") {
+			ctx.Reportf(e.Path, e.Node, "%s doesn't support interactivity", ctx.Profile.Name)
+		}
+	}
+}
+
+func checkRequiresRule(ctx *LintContext) {
+	caps := ctx.Profile.hostConfig().HostCapabilities
+	for _, e := range ctx.Elements() {
+		if missing := caps.unsatisfied(nodeRequires(e.Node)); len(missing) > 0 {
+			ctx.Reportf(e.Path, e.Node, "requirements %s are not satisfied by %s, fallback is used",
+				strings.Join(missing, ", "), ctx.Profile.Name)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package cards
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	var actions []Node
+	for i := 0; i < 7; i++ {
+		actions = append(actions, &ActionSubmit{Title: "Submit"})
+	}
+	c := New([]Node{
+		&Media{Sources: []*MediaSource{{MimeType: "video/mp4", URL: "https://adaptivecards.io/content/cat.mp4"}}},
+		&Media{
+			Sources:  []*MediaSource{{MimeType: "video/mp4", URL: "https://adaptivecards.io/content/cat.mp4"}},
+			Fallback: FallbackDrop(),
+		},
+		&TextBlock{Text: "foo", Requires: map[string]string{"acTest": "1.0"}},
+	}, []Node{
+		&ActionShowCard{Title: "Show", Card: NestedCard{
+			Actions: []Node{&ActionShowCard{Title: "Nested"}},
+		}},
+	}).WithVersion(Version15)
+	c.Body = append(c.Body, &ActionSet{Actions: actions})
+
+	issues, err := LintFor(c, ProfileTeams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, i := range issues {
+		got = append(got, i.String())
+	}
+	expected := []string{
+		"/body/0: error [unsupported-element] Media is not supported by teams, add fallback",
+		"/body/3: warning [max-actions] 7 actions are used, teams shows only 6",
+		"/actions/0/card/actions/0: error [nested-show-card] Action.ShowCard inside a shown card is not supported by teams",
+		"/body/2: info [requires] requirements acTest 1.0 are not satisfied by teams, fallback is used",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%v\nbut got:\n%v", expected, got)
+	}
+	if !issues.HasErrors() {
+		t.Error("expected to have errors")
+	}
+
+	issues, err = LintFor(c, ProfileWebex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) == 0 || issues[0].String() != "error [version] card version 1.5 is not supported by webex, the newest supported version is 1.3" {
+		t.Errorf("expected version issue, got %v", issues)
+	}
+
+	if _, err := LintFor(c, "foo"); err == nil {
+		t.Error("expected to have an error, got nil")
+	}
+}
+
+func TestLintNilProfile(t *testing.T) {
+	var actions []Node
+	for i := 0; i < 7; i++ {
+		actions = append(actions, &ActionSubmit{Title: "Submit"})
+	}
+	c := New([]Node{
+		&Media{Sources: []*MediaSource{{MimeType: "video/mp4", URL: "https://adaptivecards.io/content/cat.mp4"}}},
+		&TextBlock{Text: "foo", Requires: map[string]string{"acTest": "1.0"}},
+		&ActionSet{Actions: actions},
+	}, nil).WithVersion(Version15)
+	var got []string
+	for _, i := range Lint(c, nil) {
+		got = append(got, i.String())
+	}
+	expected := []string{
+		"/body/2: warning [max-actions] 7 actions are used, host shows only 5",
+		"/body/1: info [requires] requirements acTest 1.0 are not satisfied by host, fallback is used",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%v\nbut got:\n%v", expected, got)
+	}
+}
+
+func TestLintProfile(t *testing.T) {
+	hc := DefaultHostConfig()
+	hc.SupportsInteractivity = false
+	p := &HostProfile{
+		Name:       "static",
+		Config:     hc,
+		Severities: map[string]Severity{"interactivity": SeverityWarning},
+	}
+	c := New([]Node{&InputText{ID: "name"}}, nil)
+	issues := Lint(c, p)
+	if len(issues) != 1 || issues[0].String() != "/body/0: warning [interactivity] static doesn't support interactivity" {
+		t.Errorf("expected interactivity warning, got %v", issues)
+	}
+}
+
+func TestRegisterLintRule(t *testing.T) {
+	RegisterLintRule(LintRule{
+		Name:     "test-no-empty-container",
+		Severity: SeverityWarning,
+		Check: func(ctx *LintContext) {
+			for _, e := range ctx.Elements() {
+				if c, ok := e.Node.(*Container); ok && len(c.Items) == 0 {
+					ctx.Report(e.Path, c, "container is empty")
+				}
+			}
+		},
+	})
+	c := New([]Node{&Container{ID: "empty"}}, nil)
+	issues := Lint(c, &HostProfile{Name: "test"})
+	if len(issues) != 1 || issues[0].ID != "empty" || issues[0].Rule != "test-no-empty-container" {
+		t.Errorf("expected custom rule issue, got %v", issues)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate rule")
+		}
+	}()
+	RegisterLintRule(LintRule{Name: "test-no-empty-container", Check: func(*LintContext) {}})
+}
+
+func TestRegisterHostProfile(t *testing.T) {
+	p := &HostProfile{Name: "test-profile", MaxVersion: Version13, UnsupportedElements: []string{MediaType}}
+	RegisterHostProfile(p)
+	p.MaxVersion = Version15
+	p.UnsupportedElements[0] = ImageType
+
+	got, ok := LookupHostProfile("test-profile")
+	if !ok || got.MaxVersion != Version13 || got.UnsupportedElements[0] != MediaType {
+		t.Errorf("expected registered profile to be unchanged, got %+v", got)
+	}
+
+	teams, _ := LookupHostProfile(ProfileTeams)
+	teams.Config.SupportsInteractivity = false
+	teams.Severities = map[string]Severity{"version": SeverityInfo}
+	teams.UnsupportedElements[0] = ImageType
+	teams, _ = LookupHostProfile(ProfileTeams)
+	if !teams.Config.SupportsInteractivity || teams.Severities != nil || teams.UnsupportedElements[0] != MediaType {
+		t.Errorf("expected built-in profile to be unchanged, got %+v", teams)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate profile")
+		}
+	}()
+	RegisterHostProfile(&HostProfile{Name: ProfileTeams})
+}
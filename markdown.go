@@ -0,0 +1,183 @@
+package cards
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TextBlock supports a subset of markdown: **bold**, _italic_, [links](url),
+// bulleted and numbered lists. Paragraphs are separated with blank lines.
+
+var (
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldStar   = regexp.MustCompile(`\*\*([^\s*](?:.*?[^\s*])?)\*\*`)
+	mdBoldLine   = regexp.MustCompile(`__([^\s_](?:.*?[^\s_])?)__`)
+	mdItalicStar = regexp.MustCompile(`(^|[^\w*])\*([^\s*](?:[^*]*[^\s*])?)\*`)
+	mdItalicLine = regexp.MustCompile(`(^|[^\w_])_([^\s_](?:[^_]*[^\s_])?)_`)
+	mdBullet     = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdNumbered   = regexp.MustCompile(`^\s*(\d+)\.\s+(.*)$`)
+)
+
+// mdBlock is a paragraph or a list of markdown text
+type mdBlock struct {
+	lines   []string // paragraph lines or list items
+	list    bool
+	ordered bool
+	start   string // number of the first item of ordered list
+}
+
+// parseMarkdownBlocks splits markdown text into paragraphs and lists
+func parseMarkdownBlocks(text string) []mdBlock {
+	var blocks []mdBlock
+	var cur *mdBlock
+	flush := func() {
+		if cur != nil {
+			blocks = append(blocks, *cur)
+			cur = nil
+		}
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if m := mdBullet.FindStringSubmatch(line); m != nil {
+			if cur == nil || !cur.list || cur.ordered {
+				flush()
+				cur = &mdBlock{list: true}
+			}
+			cur.lines = append(cur.lines, m[1])
+			continue
+		}
+		if m := mdNumbered.FindStringSubmatch(line); m != nil {
+			if cur == nil || !cur.list || !cur.ordered {
+				flush()
+				cur = &mdBlock{list: true, ordered: true, start: m[1]}
+			}
+			cur.lines = append(cur.lines, m[2])
+			continue
+		}
+		if cur == nil || cur.list {
+			flush()
+			cur = &mdBlock{}
+		}
+		cur.lines = append(cur.lines, line)
+	}
+	flush()
+	return blocks
+}
+
+// markdownHTML renders markdown subset as HTML
+func markdownHTML(text string) string {
+	var b strings.Builder
+	for _, block := range parseMarkdownBlocks(text) {
+		switch {
+		case block.list && block.ordered:
+			if block.start != "1" {
+				b.WriteString(`<ol start="` + block.start + `">`)
+			} else {
+				b.WriteString("<ol>")
+			}
+			for _, item := range block.lines {
+				b.WriteString("<li>" + markdownInlineHTML(item) + "</li>")
+			}
+			b.WriteString("</ol>")
+		case block.list:
+			b.WriteString("<ul>")
+			for _, item := range block.lines {
+				b.WriteString("<li>" + markdownInlineHTML(item) + "</li>")
+			}
+			b.WriteString("</ul>")
+		default:
+			lines := make([]string, 0, len(block.lines))
+			for _, l := range block.lines {
+				lines = append(lines, markdownInlineHTML(l))
+			}
+			b.WriteString("<p>" + strings.Join(lines, "<br>") + "</p>")
+		}
+	}
+	return b.String()
+}
+
+// markdownInlineHTML renders links and emphasis of a single line as HTML
+func markdownInlineHTML(line string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range mdLink.FindAllStringSubmatchIndex(line, -1) {
+		b.WriteString(emphasisHTML(line[last:m[0]]))
+		text, url := line[m[2]:m[3]], line[m[4]:m[5]]
+		if isSafeURL(url) {
+			b.WriteString(`<a href="` + html.EscapeString(url) + `" target="_blank" rel="noopener">` + emphasisHTML(text) + "</a>")
+		} else {
+			b.WriteString(emphasisHTML(text))
+		}
+		last = m[1]
+	}
+	b.WriteString(emphasisHTML(line[last:]))
+	return b.String()
+}
+
+func emphasisHTML(s string) string {
+	s = html.EscapeString(s)
+	s = mdBoldStar.ReplaceAllString(s, "<strong>$1</strong>")
+	s = mdBoldLine.ReplaceAllString(s, "<strong>$1</strong>")
+	s = mdItalicStar.ReplaceAllString(s, "$1<em>$2</em>")
+	return mdItalicLine.ReplaceAllString(s, "$1<em>$2</em>")
+}
+
+// isSafeURL tells if the link can be rendered, script URLs are not allowed
+func isSafeURL(url string) bool {
+	u := strings.ToLower(strings.TrimSpace(url))
+	for _, scheme := range []string{"http://", "https://", "mailto:", "tel:"} {
+		if strings.HasPrefix(u, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// markdownText renders markdown subset as plain text, links are written as "text (url)"
+func markdownText(text string) string {
+	blocks := parseMarkdownBlocks(text)
+	res := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		lines := make([]string, 0, len(block.lines))
+		n, _ := strconv.Atoi(block.start)
+		for i, l := range block.lines {
+			l = markdownInlineText(l)
+			switch {
+			case block.list && block.ordered:
+				l = strconv.Itoa(n+i) + ". " + l
+			case block.list:
+				l = "- " + l
+			}
+			lines = append(lines, l)
+		}
+		res = append(res, strings.Join(lines, "\n"))
+	}
+	return strings.Join(res, "\n\n")
+}
+
+// markdownInlineText removes emphasis of a single line and replaces links with their text and url
+func markdownInlineText(line string) string {
+	line = mdLink.ReplaceAllStringFunc(line, func(link string) string {
+		m := mdLink.FindStringSubmatch(link)
+		if m[1] == m[2] {
+			return m[2]
+		}
+		return m[1] + " (" + m[2] + ")"
+	})
+	line = mdBoldStar.ReplaceAllString(line, "$1")
+	line = mdBoldLine.ReplaceAllString(line, "$1")
+	line = mdItalicStar.ReplaceAllString(line, "$1$2")
+	return mdItalicLine.ReplaceAllString(line, "$1$2")
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`", "|", `\|`)
+
+// escapeMarkdown escapes plain text to be used in markdown
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
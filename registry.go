@@ -0,0 +1,85 @@
+package cards
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]func() Node{
+		TextBlockType:              func() Node { return &TextBlock{} },
+		ImageType:                  func() Node { return &Image{} },
+		MediaType:                  func() Node { return &Media{} },
+		RichTextBlockType:          func() Node { return &RichTextBlock{} },
+		TextRunType:                func() Node { return &TextRun{} },
+		ActionSetType:              func() Node { return &ActionSet{} },
+		ContainerType:              func() Node { return &Container{} },
+		ColumnSetType:              func() Node { return &ColumnSet{} },
+		ColumnType:                 func() Node { return &Column{} },
+		FactSetType:                func() Node { return &FactSet{} },
+		ImageSetType:               func() Node { return &ImageSet{} },
+		ActionShowCardType:         func() Node { return &ActionShowCard{} },
+		ActionSubmitType:           func() Node { return &ActionSubmit{} },
+		ActionOpenURLType:          func() Node { return &ActionOpenURL{} },
+		ActionToggleVisibilityType: func() Node { return &ActionToggleVisibility{} },
+		InputTextType:              func() Node { return &InputText{} },
+		InputNumberType:            func() Node { return &InputNumber{} },
+		InputTimeType:              func() Node { return &InputTime{} },
+		InputDateType:              func() Node { return &InputDate{} },
+		InputChoiceSetType:         func() Node { return &InputChoiceSet{} },
+		InputToggleType:            func() Node { return &InputToggle{} },
+	}
+)
+
+// RegisterType makes custom element type available for serialization and decoding.
+// Factory must return pointer to a new zero element, it is used by the decoder
+// to create elements with "type" equal to typeName.
+// Custom element is responsible for its JSON representation including "type" field.
+// RegisterType panics if typeName is empty, factory is nil or the type is already registered.
+func RegisterType(typeName string, factory func() Node) {
+	if typeName == "" {
+		panic("cards: RegisterType type name is empty")
+	}
+	if factory == nil {
+		panic("cards: RegisterType factory is nil for " + typeName)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[typeName]; dup {
+		panic("cards: RegisterType called twice for " + typeName)
+	}
+	registry[typeName] = factory
+}
+
+// RegisteredTypes returns names of all known element types
+func RegisteredTypes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	types := make([]string, 0, len(registry))
+	for t := range registry {
+		types = append(types, t)
+	}
+	return types
+}
+
+func lookupType(typeName string) (func() Node, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[typeName]
+	return factory, ok
+}
+
+// prepareNode checks element type is registered and prepares the element with its fallback
+func prepareNode(n Node) error {
+	if n == nil {
+		return fmt.Errorf("element is nil")
+	}
+	if _, ok := lookupType(n.NodeType()); !ok {
+		return fmt.Errorf("element type %q is not registered", n.NodeType())
+	}
+	if err := n.Prepare(); err != nil {
+		return err
+	}
+	return prepareFallback(n)
+}
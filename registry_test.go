@@ -0,0 +1,70 @@
+package cards
+
+import (
+	"strings"
+	"testing"
+)
+
+const testBadgeType = "Test.Badge"
+
+type testBadge struct {
+	Type         string `json:"type"`
+	Label        string `json:"label"`
+	SelectAction Node   `json:"selectAction,omitempty"`
+}
+
+func (b *testBadge) NodeType() string {
+	return testBadgeType
+}
+
+func (b *testBadge) Prepare() error {
+	b.Type = testBadgeType
+	return nil
+}
+
+type testUnregistered struct{}
+
+func (u *testUnregistered) NodeType() string { return "Test.Unregistered" }
+func (u *testUnregistered) Prepare() error   { return nil }
+
+func init() {
+	RegisterType(testBadgeType, func() Node { return &testBadge{} })
+}
+
+func TestRegisteredTypeRoundTrip(t *testing.T) {
+	cardJSON := `{"type":"AdaptiveCard","version":"1.3","body":[{"type":"Test.Badge","label":"new","selectAction":{"type":"Action.OpenUrl","url":"https://adaptivecards.io"}}]}`
+	c, err := Parse(strings.NewReader(cardJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	badge, ok := c.Body[0].(*testBadge)
+	if !ok {
+		t.Fatalf("expected *testBadge, got %T", c.Body[0])
+	}
+	if _, ok := badge.SelectAction.(*ActionOpenURL); !ok {
+		t.Errorf("expected *ActionOpenURL, got %T", badge.SelectAction)
+	}
+	got, err := c.String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != cardJSON {
+		t.Errorf("expected:\n%s\nbut got:\n%s", cardJSON, got)
+	}
+}
+
+func TestUnregisteredTypeIsRejected(t *testing.T) {
+	c := New([]Node{&testUnregistered{}}, nil)
+	if _, err := c.String(); err == nil {
+		t.Error("expected to have an error, got nil")
+	}
+}
+
+func TestRegisterTypeTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	RegisterType(TextBlockType, func() Node { return &TextBlock{} })
+}
@@ -0,0 +1,193 @@
+package cards
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// anyVersion is requirement satisfied by any version of the feature
+const anyVersion = "*"
+
+// HostCapabilities maps names of features supported by the host to their versions,
+// e.g. {"adaptiveCards": "1.5", "acTest": "1.0"}.
+// Elements declare features they need in their "requires" property.
+type HostCapabilities map[string]string
+
+// Supports tells if the host supports the feature of the version or newer.
+// Version "*" is satisfied by any version of the feature.
+func (h HostCapabilities) Supports(feature, version string) bool {
+	hostVersion, ok := h[feature]
+	if !ok {
+		return false
+	}
+	if strings.TrimSpace(version) == anyVersion {
+		return true
+	}
+	cmp, err := compareFeatureVersions(hostVersion, version)
+	return err == nil && cmp >= 0
+}
+
+// Satisfies tells if the host supports all the requirements
+func (h HostCapabilities) Satisfies(requires map[string]string) bool {
+	return len(h.unsatisfied(requires)) == 0
+}
+
+// unsatisfied returns sorted descriptions of requirements the host doesn't support
+func (h HostCapabilities) unsatisfied(requires map[string]string) []string {
+	var res []string
+	for feature, version := range requires {
+		if !h.Supports(feature, version) {
+			res = append(res, fmt.Sprintf("%s %s", feature, version))
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// compareFeatureVersions compares dotted versions like "1.0" and "1.0.2", missing parts are zeros
+func compareFeatureVersions(a, b string) (int, error) {
+	ap, err := parseFeatureVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	bp, err := parseFeatureVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for len(ap) < len(bp) {
+		ap = append(ap, 0)
+	}
+	for len(bp) < len(ap) {
+		bp = append(bp, 0)
+	}
+	for i := range ap {
+		if ap[i] != bp[i] {
+			return sign(ap[i] - bp[i]), nil
+		}
+	}
+	return 0, nil
+}
+
+func parseFeatureVersion(v string) ([]int, error) {
+	parts := strings.Split(strings.TrimSpace(v), ".")
+	res := make([]int, 0, len(parts))
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid feature version %q", v)
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+// Resolve returns copy of the card as it is rendered by the host with provided capabilities.
+// Elements which requirements are not satisfied are replaced with their fallback.
+// Element without fallback is handled by the fallback of the closest ancestor having one,
+// if there is no such ancestor the element is dropped.
+// Returned warnings describe every change. The card itself is not modified.
+func Resolve(c *Card, caps HostCapabilities) (*Card, []Warning) {
+	res := c.Clone()
+	r := &resolver{caps: caps}
+	r.children(res, "", false)
+	return res, r.warnings
+}
+
+type resolver struct {
+	caps     HostCapabilities
+	warnings []Warning
+}
+
+func (r *resolver) warn(path string, n Node, msg string) {
+	r.warnings = append(r.warnings, Warning{Path: path, Type: n.NodeType(), ID: nodeID(n), Message: msg})
+}
+
+// children resolves child elements of the parent.
+// If bubble is true an ancestor has fallback, so unsupported elements without fallback
+// are not dropped but reported by returning true.
+func (r *resolver) children(parent interface{}, path string, bubble bool) bool {
+	failed := false
+	for _, s := range slots(parent) {
+		if s.name == "fallback" {
+			continue // fallback is resolved when used
+		}
+		slotPath := path + "/" + s.name
+		switch {
+		case s.list != nil:
+			if *s.list == nil {
+				continue
+			}
+			nodes, fail := r.list(*s.list, slotPath, bubble)
+			failed = failed || fail
+			*s.list = nodes
+		case s.node != nil:
+			n, fail := r.node(*s.node, slotPath, bubble)
+			failed = failed || fail
+			*s.node = n
+		default:
+			nodes, fail := r.list(s.nodes, slotPath, bubble)
+			failed = failed || fail
+			s.setNodes(nodes)
+		}
+	}
+	return failed
+}
+
+func (r *resolver) list(nodes []Node, path string, bubble bool) ([]Node, bool) {
+	res := make([]Node, 0, len(nodes))
+	failed := false
+	for i, n := range nodes {
+		resolved, fail := r.node(n, fmt.Sprintf("%s/%d", path, i), bubble)
+		if fail {
+			failed = true
+			continue
+		}
+		if resolved != nil || n == nil {
+			res = append(res, resolved)
+		}
+	}
+	return res, failed
+}
+
+// node returns the element, its fallback or nil if the element is dropped
+func (r *resolver) node(n Node, path string, bubble bool) (Node, bool) {
+	if isNilNode(n) {
+		return n, false
+	}
+	if missing := r.caps.unsatisfied(nodeRequires(n)); len(missing) > 0 {
+		return r.fallback(n, path, bubble, fmt.Sprintf("requirements %s are not satisfied", strings.Join(missing, ", ")))
+	}
+	if r.children(n, path, bubble || nodeFallback(n) != nil) {
+		return r.fallback(n, path, bubble, "requirements of child element are not satisfied")
+	}
+	return n, false
+}
+
+func (r *resolver) fallback(n Node, path string, bubble bool, reason string) (Node, bool) {
+	f := nodeFallback(n)
+	switch {
+	case f != nil && f.Element != nil:
+		r.warn(path, n, reason+", element is replaced with its fallback")
+		return r.node(f.Element, path+"/fallback", bubble)
+	case f != nil && f.Drop:
+		r.warn(path, n, reason+", element is dropped")
+		return nil, false
+	case bubble:
+		r.warn(path, n, reason+", fallback of parent element is used")
+		return nil, true
+	}
+	r.warn(path, n, reason+", element is dropped")
+	return nil, false
+}
+
+// nodeRequires returns requirements of the element
+func nodeRequires(n Node) map[string]string {
+	f := structField(n, "Requires")
+	if !f.IsValid() {
+		return nil
+	}
+	requires, _ := f.Interface().(map[string]string)
+	return requires
+}
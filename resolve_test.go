@@ -0,0 +1,95 @@
+package cards
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	c := New([]Node{
+		&TextBlock{Text: "always"},
+		&TextBlock{
+			Text:     "new feature",
+			Requires: map[string]string{"acTest": "1.1"},
+			Fallback: FallbackElement(&TextBlock{Text: "old feature"}),
+		},
+		&TextBlock{Text: "dropped", Requires: map[string]string{"unknown": "*"}},
+		&Container{
+			Items: []Node{
+				&TextBlock{Text: "inside", Requires: map[string]string{"unknown": "1.0"}},
+			},
+			Fallback: FallbackElement(&TextBlock{Text: "container fallback"}),
+		},
+		&ColumnSet{Columns: []*Column{
+			{Items: []Node{&TextBlock{Text: "column"}}},
+			{
+				Items:    []Node{&TextBlock{Text: "new column"}},
+				Requires: map[string]string{"acTest": "2.0"},
+				Fallback: FallbackDrop(),
+			},
+		}},
+	}, []Node{
+		&ActionSubmit{Title: "Submit", Requires: map[string]string{"acTest": "1.0.0"}},
+	})
+	caps := HostCapabilities{"acTest": "1.1"}
+
+	res, warnings := Resolve(c, caps)
+	var got []string
+	for _, w := range warnings {
+		got = append(got, w.String())
+	}
+	expected := []string{
+		"/body/2: requirements unknown * are not satisfied, element is dropped",
+		"/body/3/items/0: requirements unknown 1.0 are not satisfied, fallback of parent element is used",
+		"/body/3: requirements of child element are not satisfied, element is replaced with its fallback",
+		"/body/4/columns/1: requirements acTest 2.0 are not satisfied, element is dropped",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%v\nbut got:\n%v", expected, got)
+	}
+
+	var texts []string
+	for _, n := range res.Body {
+		if tb, ok := n.(*TextBlock); ok {
+			texts = append(texts, tb.Text)
+		}
+	}
+	expectedTexts := []string{"always", "new feature", "container fallback"}
+	if !reflect.DeepEqual(texts, expectedTexts) {
+		t.Errorf("expected text blocks %v, got %v", expectedTexts, texts)
+	}
+	if columns := res.Body[3].(*ColumnSet).Columns; len(columns) != 1 {
+		t.Errorf("expected 1 column, got %d", len(columns))
+	}
+	if len(res.Actions) != 1 {
+		t.Errorf("expected action to be kept, got %d actions", len(res.Actions))
+	}
+	if len(c.Body) != 5 {
+		t.Error("original card is modified")
+	}
+
+	res, _ = Resolve(c, HostCapabilities{})
+	if tb := res.Body[1].(*TextBlock); tb.Text != "old feature" {
+		t.Errorf("expected fallback to be used, got %q", tb.Text)
+	}
+}
+
+func TestHostCapabilitiesSupports(t *testing.T) {
+	caps := HostCapabilities{"acTest": "1.2.1"}
+	cases := []struct {
+		feature, version string
+		expected         bool
+	}{
+		{"acTest", "*", true},
+		{"acTest", "1.2", true},
+		{"acTest", "1.2.1", true},
+		{"acTest", "1.10", false},
+		{"acTest", "foo", false},
+		{"other", "*", false},
+	}
+	for _, c := range cases {
+		if got := caps.Supports(c.feature, c.version); got != c.expected {
+			t.Errorf("Supports(%q, %q): expected %v, got %v", c.feature, c.version, c.expected, got)
+		}
+	}
+}
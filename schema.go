@@ -0,0 +1,443 @@
+package cards
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// JSONSchemaDraft is $schema of exported schemas
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the subset of JSON Schema (draft 2020-12) used to describe form data:
+// types, enum and oneOf of consts, minimum/maximum, maxLength, pattern, formats and nested objects
+type JSONSchema struct {
+	Schema               string           `json:"$schema,omitempty"`
+	Title                string           `json:"title,omitempty"`
+	Description          string           `json:"description,omitempty"`
+	Type                 SchemaType       `json:"type,omitempty"`
+	Properties           SchemaProperties `json:"properties,omitempty"`
+	Required             []string         `json:"required,omitempty"`
+	AdditionalProperties *bool            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema      `json:"items,omitempty"`
+	UniqueItems          bool             `json:"uniqueItems,omitempty"`
+	Enum                 []interface{}    `json:"enum,omitempty"`
+	OneOf                []*JSONSchema    `json:"oneOf,omitempty"`
+	Const                interface{}      `json:"const,omitempty"`
+	Default              interface{}      `json:"default,omitempty"`
+	Minimum              *float64         `json:"minimum,omitempty"`
+	Maximum              *float64         `json:"maximum,omitempty"`
+	MaxLength            *int64           `json:"maxLength,omitempty"`
+	Pattern              string           `json:"pattern,omitempty"`
+	Format               string           `json:"format,omitempty"`
+}
+
+// SchemaType is "type" of JSON Schema, which is a type name or a list of them, e.g. ["string", "null"]
+type SchemaType []string
+
+// Is tells if the type list contains the type
+func (t SchemaType) Is(name string) bool {
+	for _, s := range t {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON encodes single type as a string
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON decodes type name or list of names
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = SchemaType{name}
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return errors.New("schema type must be a string or a list of strings")
+	}
+	*t = names
+	return nil
+}
+
+// SchemaProperty is a named property of object schema
+type SchemaProperty struct {
+	Name   string
+	Schema *JSONSchema
+}
+
+// SchemaProperties are object properties in document order, which is the order of form inputs
+type SchemaProperties []SchemaProperty
+
+// Get returns schema of the property or nil
+func (p SchemaProperties) Get(name string) *JSONSchema {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Schema
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes properties as JSON object keeping the order
+func (p SchemaProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(prop.Name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(prop.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(schema)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes JSON object keeping the order of properties
+func (p *SchemaProperties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return errors.New("schema properties must be an object")
+	}
+	var res SchemaProperties
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var schema JSONSchema
+		if err := dec.Decode(&schema); err != nil {
+			return fmt.Errorf("property %s: %w", t, err)
+		}
+		res = append(res, SchemaProperty{Name: t.(string), Schema: &schema})
+	}
+	*p = res
+	return nil
+}
+
+// ParseJSONSchema reads JSON Schema document
+func ParseJSONSchema(r io.Reader) (*JSONSchema, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var s JSONSchema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// FormFromSchema returns a card with inputs for the properties of object schema and Action.Submit.
+// Strings become Input.Text (Input.Date and Input.Time for date and time formats), numbers and integers
+// Input.Number, booleans Input.Toggle, enums (and oneOf consts with titles) Input.ChoiceSet,
+// arrays of enums multi-select Input.ChoiceSet. Nested objects become Container sections,
+// IDs of their inputs are property paths joined with dot, e.g. "address.city".
+// Schema title is used as the form title unless it is set in options.
+func FormFromSchema(s *JSONSchema, opts FormOptions) (*Card, error) {
+	if s == nil || !s.Type.Is("object") {
+		return nil, errors.New("form schema must be an object")
+	}
+	var body []Node
+	if title := firstNonEmpty(opts.Title, s.Title); title != "" {
+		body = append(body, &TextBlock{Text: title, Size: SizeMedium, Weight: WeightBolder, Wrap: TruePtr()})
+	}
+	inputs, err := schemaInputs(s, "")
+	if err != nil {
+		return nil, err
+	}
+	body = append(body, inputs...)
+	c := New(body, []Node{&ActionSubmit{
+		Title: firstNonEmpty(opts.SubmitTitle, "Submit"),
+		Data:  opts.SubmitData,
+	}})
+	if opts.Version != "" {
+		c.Version = opts.Version
+	}
+	return c, nil
+}
+
+// schemaInputs returns inputs for the properties of object schema
+func schemaInputs(s *JSONSchema, prefix string) ([]Node, error) {
+	var res []Node
+	for _, prop := range s.Properties {
+		if prop.Schema == nil {
+			continue
+		}
+		required := false
+		for _, name := range s.Required {
+			if name == prop.Name {
+				required = true
+			}
+		}
+		n, err := schemaInput(prop.Schema, prefix+prop.Name, required)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", prefix+prop.Name, err)
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+// schemaInput returns input for the property schema, object properties become a container
+func schemaInput(s *JSONSchema, id string, required bool) (Node, error) {
+	label := s.Title
+	if label == "" {
+		// property names like due_date or dueDate become "Due date"
+		name := []rune(humanize(strings.ReplaceAll(id[strings.LastIndexByte(id, '.')+1:], "_", " ")))
+		if len(name) > 0 {
+			name[0] = unicode.ToUpper(name[0])
+		}
+		label = string(name)
+	}
+	var isRequired *bool
+	if required {
+		isRequired = TruePtr()
+	}
+	if choices := schemaChoices(s); choices != nil {
+		return &InputChoiceSet{ID: id, Label: label, Choices: choices, Value: schemaValue(s.Default),
+			Placeholder: s.Description, IsRequired: isRequired}, nil
+	}
+	switch {
+	case s.Type.Is("object"):
+		items := []Node{&TextBlock{Text: label, Weight: WeightBolder, Wrap: TruePtr()}}
+		inputs, err := schemaInputs(s, id+".")
+		if err != nil {
+			return nil, err
+		}
+		return &Container{Items: append(items, inputs...)}, nil
+	case s.Type.Is("array"):
+		choices := schemaChoices(s.Items)
+		if choices == nil {
+			return nil, errors.New("only arrays of enums are supported")
+		}
+		var values []string
+		if list, ok := s.Default.([]interface{}); ok {
+			for _, v := range list {
+				values = append(values, schemaValue(v))
+			}
+		}
+		return &InputChoiceSet{ID: id, Label: label, Choices: choices, IsMultiSelect: TruePtr(),
+			Value: strings.Join(values, ","), Placeholder: s.Description, IsRequired: isRequired}, nil
+	case s.Type.Is("boolean"):
+		value := "false"
+		if v, _ := s.Default.(bool); v {
+			value = "true"
+		}
+		// required only means the key is present, the toggle can be off
+		return &InputToggle{ID: id, Title: label, Value: value}, nil
+	case s.Type.Is("number") || s.Type.Is("integer"):
+		input := &InputNumber{ID: id, Label: label, Placeholder: s.Description, IsRequired: isRequired}
+		input.Value, _ = s.Default.(float64)
+		if s.Minimum != nil {
+			input.Min = FloatPtr(*s.Minimum)
+		}
+		if s.Maximum != nil {
+			input.Max = FloatPtr(*s.Maximum)
+		}
+		return input, nil
+	case s.Type.Is("string") || len(s.Type) == 0:
+	default:
+		return nil, fmt.Errorf("unsupported type %s", strings.Join(s.Type, ", "))
+	}
+	switch s.Format {
+	case "date":
+		return &InputDate{ID: id, Label: label, Value: schemaValue(s.Default), Placeholder: s.Description,
+			IsRequired: isRequired}, nil
+	case "time":
+		return &InputTime{ID: id, Label: label, Value: schemaValue(s.Default), Placeholder: s.Description,
+			IsRequired: isRequired}, nil
+	}
+	input := &InputText{ID: id, Label: label, Value: schemaValue(s.Default), Placeholder: s.Description,
+		Regex: s.Pattern, IsRequired: isRequired}
+	if s.MaxLength != nil {
+		input.MaxLength = *s.MaxLength
+	}
+	switch s.Format {
+	case "email":
+		input.Style = TextInputStyleEmail
+	case "uri", "url":
+		input.Style = TextInputStyleURL
+	}
+	return input, nil
+}
+
+// schemaChoices returns choices of enum or oneOf consts, nil if schema isn't an enum
+func schemaChoices(s *JSONSchema) []*InputChoice {
+	if s == nil {
+		return nil
+	}
+	var res []*InputChoice
+	for _, v := range s.Enum {
+		if v != nil {
+			res = append(res, &InputChoice{Title: schemaValue(v), Value: schemaValue(v)})
+		}
+	}
+	for _, c := range s.OneOf {
+		if c != nil && c.Const != nil {
+			res = append(res, &InputChoice{Title: firstNonEmpty(c.Title, schemaValue(c.Const)), Value: schemaValue(c.Const)})
+		}
+	}
+	return res
+}
+
+// schemaValue returns JSON value as an input value string
+func schemaValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return formatFloat(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// SubmissionSchema returns JSON Schema of Action.Submit payload of the card. Clients send every input
+// value as a string, so values are described as strings: numbers, dates and times with patterns,
+// Input.Toggle values with enum of valueOn and valueOff, multi-select Input.ChoiceSet values with a pattern
+// of comma-joined choices. Values of inputs which are not required can be empty.
+// Number ranges aren't expressible for strings and are only mentioned in the description.
+// Inputs of Action.ShowCard cards are not required as they are sent only by actions of their cards.
+// Additional properties are allowed because action data is merged into the payload.
+func SubmissionSchema(c *Card) *JSONSchema {
+	res := &JSONSchema{
+		Schema:     JSONSchemaDraft,
+		Type:       SchemaType{"object"},
+		Properties: SchemaProperties{},
+	}
+	var walk func(parent interface{}, inShowCard bool)
+	walk = func(parent interface{}, inShowCard bool) {
+		for _, child := range childNodes(parent) {
+			n := child.node
+			if child.path == "fallback" || isNilNode(n) {
+				continue
+			}
+			if id := nodeID(n); isInput(n) && id != "" && res.Properties.Get(id) == nil {
+				res.Properties = append(res.Properties, SchemaProperty{Name: id, Schema: inputSchema(n)})
+				if required, _ := fieldValue(n, "IsRequired").(*bool); isTrue(required) && !inShowCard {
+					res.Required = append(res.Required, id)
+				}
+			}
+			_, sc := n.(*ActionShowCard)
+			walk(n, inShowCard || sc)
+		}
+	}
+	walk(c, false)
+	return res
+}
+
+const (
+	numberPattern = `-?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?`
+	datePattern   = `\d{4}-\d{2}-\d{2}`
+	timePattern   = `\d{2}:\d{2}(:\d{2})?`
+)
+
+// inputSchema returns JSON Schema of the input value as the client sends it
+func inputSchema(n Node) *JSONSchema {
+	s := &JSONSchema{Title: fieldString(n, "Label"), Type: SchemaType{"string"}}
+	required, _ := fieldValue(n, "IsRequired").(*bool)
+	// valuePattern matches the whole value, empty value is allowed for optional inputs
+	valuePattern := func(p string) string {
+		if isTrue(required) {
+			return "^(" + p + ")$"
+		}
+		return "^(" + p + ")?$"
+	}
+	switch n := n.(type) {
+	case *InputText:
+		if n.MaxLength > 0 {
+			s.MaxLength = &n.MaxLength
+		}
+		if n.Regex != "" {
+			// input regex isn't anchored like JSON Schema pattern
+			s.Pattern = n.Regex
+			if !isTrue(required) {
+				s.Pattern = "^$|" + n.Regex
+			}
+		}
+		switch n.Style {
+		case TextInputStyleEmail:
+			s.Format = "email"
+		case TextInputStyleURL:
+			s.Format = "uri"
+		}
+	case *InputNumber:
+		s.Pattern = valuePattern(numberPattern)
+		switch {
+		case n.Min != nil && n.Max != nil:
+			s.Description = fmt.Sprintf("number from %s to %s", formatFloat(*n.Min), formatFloat(*n.Max))
+		case n.Min != nil:
+			s.Description = fmt.Sprintf("number from %s", formatFloat(*n.Min))
+		case n.Max != nil:
+			s.Description = fmt.Sprintf("number up to %s", formatFloat(*n.Max))
+		}
+	case *InputDate:
+		s.Pattern = valuePattern(datePattern)
+	case *InputTime:
+		s.Pattern = valuePattern(timePattern)
+	case *InputToggle:
+		s.Title = firstNonEmpty(s.Title, n.Title)
+		on, off := toggleValues(n)
+		s.Enum = []interface{}{on, off}
+	case *InputChoiceSet:
+		if isTrue(n.IsMultiSelect) {
+			values := make([]string, 0, len(n.Choices))
+			for _, c := range n.Choices {
+				if c != nil {
+					values = append(values, regexp.QuoteMeta(c.Value))
+				}
+			}
+			choice := "(" + strings.Join(values, "|") + ")"
+			s.Pattern = valuePattern(choice + "(," + choice + ")*")
+			return s
+		}
+		var enum []interface{}
+		var oneOf []*JSONSchema
+		titled := false
+		for _, c := range n.Choices {
+			if c == nil {
+				continue
+			}
+			titled = titled || c.Title != c.Value
+			enum = append(enum, c.Value)
+			oneOf = append(oneOf, &JSONSchema{Const: c.Value, Title: c.Title})
+		}
+		if !isTrue(required) {
+			enum = append(enum, "")
+			oneOf = append(oneOf, &JSONSchema{Const: "", Title: "not selected"})
+		}
+		// titles are only kept when they differ from values
+		if titled {
+			s.OneOf = oneOf
+		} else {
+			s.Enum = enum
+		}
+	}
+	return s
+}
@@ -0,0 +1,137 @@
+package cards
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func loadSchema(t *testing.T, path string) *JSONSchema {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s, err := ParseJSONSchema(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestFormFromSchema(t *testing.T) {
+	s := loadSchema(t, "test/schema/incident.json")
+	c, err := FormFromSchema(s, FormOptions{SubmitTitle: "Report"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Node{
+		&TextBlock{Text: "Incident", Size: SizeMedium, Weight: WeightBolder, Wrap: TruePtr()},
+		&InputText{ID: "summary", Label: "Summary", MaxLength: 80, Regex: `^\S`, IsRequired: TruePtr()},
+		&InputText{ID: "details", Label: "Details", Placeholder: "What happened?"},
+		&InputChoiceSet{ID: "severity", Label: "Severity", Value: "low", IsRequired: TruePtr(),
+			Choices: []*InputChoice{{Title: "Low", Value: "low"}, {Title: "High", Value: "high"}}},
+		&InputChoiceSet{ID: "tags", Label: "Tags", IsMultiSelect: TruePtr(),
+			Choices: []*InputChoice{{Title: "db", Value: "db"}, {Title: "network", Value: "network"}}},
+		&InputNumber{ID: "affected_users", Label: "Affected users", Min: FloatPtr(0), Max: FloatPtr(1000)},
+		&InputDate{ID: "date", Label: "Date"},
+		&InputTime{ID: "started", Label: "Started"},
+		&InputToggle{ID: "resolved", Title: "Resolved", Value: "true"},
+		&Container{Items: []Node{
+			&TextBlock{Text: "Reporter", Weight: WeightBolder, Wrap: TruePtr()},
+			&InputText{ID: "reporter.email", Label: "Email", Style: TextInputStyleEmail, IsRequired: TruePtr()},
+			&InputText{ID: "reporter.homePage", Label: "Home page", Style: TextInputStyleURL},
+		}},
+	}
+	if !reflect.DeepEqual(c.Body, expected) {
+		got, _ := json.MarshalIndent(c.Body, "", "  ")
+		t.Errorf("unexpected body:\n%s", got)
+	}
+	if submit := c.Actions[0].(*ActionSubmit); submit.Title != "Report" {
+		t.Errorf("expected submit title Report but got %s", submit.Title)
+	}
+	if err := c.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFormFromSchemaErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"type": "string"}`,
+		`{"type": "object", "properties": {"list": {"type": "array", "items": {"type": "string"}}}}`,
+		`{"type": "object", "properties": {"nothing": {"type": "null"}}}`,
+	} {
+		var s JSONSchema
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := FormFromSchema(&s, FormOptions{}); err == nil {
+			t.Errorf("expected error for %s", data)
+		}
+	}
+	var s JSONSchema
+	if err := json.Unmarshal([]byte(`{"type": 1}`), &s); err == nil {
+		t.Error("expected type error")
+	}
+}
+
+func TestSubmissionSchema(t *testing.T) {
+	c, err := FormFromSchema(loadSchema(t, "test/schema/incident.json"), FormOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Actions = append(c.Actions, &ActionShowCard{Title: "Comment", Card: NestedCard{
+		Body: []Node{&InputText{ID: "comment", IsRequired: TruePtr()}},
+	}})
+	got, err := json.MarshalIndent(SubmissionSchema(c), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := mustReadFile("./test/schema/incident.submission.json")
+	if string(got) != strings.TrimSpace(expected) {
+		t.Errorf("expected\n%s\nbut got\n%s", expected, got)
+	}
+
+	// exported schema can be parsed back keeping the order of properties
+	var s JSONSchema
+	if err := json.Unmarshal(got, &s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Properties) != 11 || s.Properties[0].Name != "summary" || s.Properties[10].Name != "comment" {
+		t.Errorf("unexpected properties %v", s.Properties)
+	}
+	if tags := s.Properties.Get("tags"); tags.Pattern != "^((db|network)(,(db|network))*)?$" {
+		t.Errorf("unexpected tags schema %+v", tags)
+	}
+}
+
+func TestSubmissionSchemaRequiredToggle(t *testing.T) {
+	var s JSONSchema
+	data := `{"type": "object", "properties": {"notify": {"type": "boolean"}}, "required": ["notify"]}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+	c, err := FormFromSchema(&s, FormOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	toggle := c.Body[0].(*InputToggle)
+	if toggle.IsRequired != nil {
+		t.Errorf("expected toggle not to be required")
+	}
+	// toggle value is sent as a string
+	if p := SubmissionSchema(c).Properties.Get("notify"); !reflect.DeepEqual(p.Type, SchemaType{"string"}) ||
+		!reflect.DeepEqual(p.Enum, []interface{}{"true", "false"}) {
+		t.Errorf("unexpected toggle schema %+v", p)
+	}
+	errs, err := ValidateSubmission(c, []byte(`{"notify": "false"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("expected off toggle to be valid, got %v", errs)
+	}
+}
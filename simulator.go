@@ -0,0 +1,342 @@
+package cards
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Simulator plays a card like a client does, so card flows can be tested without a host.
+// Actions are clicked by ID or title, Action.ToggleVisibility changes element visibility,
+// Action.ShowCard expands its card and Action.Submit returns the payload the bot would receive.
+// Only visible elements and elements of expanded cards can be used.
+type Simulator struct {
+	card     *Card
+	values   map[string]string
+	expanded map[*ActionShowCard]bool
+}
+
+// ClickResult is the outcome of a click
+type ClickResult struct {
+	Action Node                   // clicked action
+	Data   map[string]interface{} // Action.Submit payload: action data merged with input values
+	URL    string                 // Action.OpenUrl url
+}
+
+// InputError is an input value rejected by a client
+type InputError struct {
+	ID      string
+	Message string
+}
+
+// InputErrors is a list of rejected input values
+type InputErrors []InputError
+
+func (e InputErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, ie := range e {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", ie.ID, ie.Message))
+	}
+	return "invalid input: " + strings.Join(msgs, "; ")
+}
+
+// NewSimulator returns simulator of a copy of the card with inputs set to their initial values
+func NewSimulator(c *Card) (*Simulator, error) {
+	if err := c.Prepare(); err != nil {
+		return nil, err
+	}
+	s := &Simulator{
+		card:     c.Clone(),
+		values:   map[string]string{},
+		expanded: map[*ActionShowCard]bool{},
+	}
+	walkTree(s.card, "", func(path string, n Node) {
+		if id := nodeID(n); id != "" && isInput(n) {
+			s.values[id] = initialValue(n)
+		}
+	})
+	return s, nil
+}
+
+// Card returns simulated card, visibility of its elements reflects toggles made so far
+func (s *Simulator) Card() *Card {
+	return s.card
+}
+
+// Value returns current value of the input as the client would submit it
+func (s *Simulator) Value(id string) string {
+	return s.values[id]
+}
+
+// IsVisible tells if the element with the ID can be seen: it and its ancestors are visible
+// and it isn't in a collapsed Action.ShowCard
+func (s *Simulator) IsVisible(id string) bool {
+	found := false
+	s.walk(func(n Node, scopes []Node) {
+		if nodeID(n) == id {
+			found = true
+		}
+	})
+	return found
+}
+
+// IsExpanded tells if the card of Action.ShowCard with given ID or title is shown
+func (s *Simulator) IsExpanded(action string) bool {
+	for sc, expanded := range s.expanded {
+		if expanded && (sc.ID == action || sc.Title == action) {
+			return true
+		}
+	}
+	return false
+}
+
+// Actions returns actions which can be clicked now in document order
+func (s *Simulator) Actions() []Node {
+	var res []Node
+	s.walk(func(n Node, scopes []Node) {
+		if isAction(n) {
+			res = append(res, n)
+		}
+	})
+	return res
+}
+
+// Set fills the input with ID. Value is a string as a client sends it,
+// a number for Input.Number, bool for Input.Toggle or []string for multi-select Input.ChoiceSet.
+func (s *Simulator) Set(id string, value interface{}) error {
+	var input Node
+	s.walk(func(n Node, scopes []Node) {
+		if isInput(n) && nodeID(n) == id {
+			input = n
+		}
+	})
+	if input == nil {
+		return fmt.Errorf("input %q is not found or not visible", id)
+	}
+	v, err := inputValue(input, value)
+	if err != nil {
+		return fmt.Errorf("input %q: %w", id, err)
+	}
+	s.values[id] = v
+	return nil
+}
+
+// Click invokes visible action with given ID or title
+func (s *Simulator) Click(action string) (*ClickResult, error) {
+	type match struct {
+		action Node
+		scopes []Node
+	}
+	var byID, byTitle []match
+	s.walk(func(n Node, scopes []Node) {
+		switch {
+		case !isAction(n):
+		case nodeID(n) == action:
+			byID = append(byID, match{n, scopes})
+		case fieldString(n, "Title") == action:
+			byTitle = append(byTitle, match{n, scopes})
+		}
+	})
+	found := byID
+	if len(found) == 0 {
+		found = byTitle
+	}
+	switch {
+	case len(found) == 0:
+		return nil, fmt.Errorf("action %q is not found or not visible", action)
+	case len(found) > 1:
+		return nil, fmt.Errorf("action %q is ambiguous, %d actions match", action, len(found))
+	}
+	return s.invoke(found[0].action, found[0].scopes)
+}
+
+func (s *Simulator) invoke(a Node, scopes []Node) (*ClickResult, error) {
+	res := &ClickResult{Action: a}
+	switch a := a.(type) {
+	case *ActionOpenURL:
+		res.URL = a.URL
+	case *ActionShowCard:
+		s.expanded[a] = !s.expanded[a]
+	case *ActionToggleVisibility:
+		for _, t := range a.TargetElements {
+			s.toggle(t)
+		}
+	case *ActionSubmit:
+		data := map[string]interface{}{}
+		for k, v := range a.Data {
+			data[k] = v
+		}
+		if !strings.EqualFold(a.AssociatedInputs, "none") {
+			inputs := scopeInputs(s.card, scopes)
+			var errs InputErrors
+			for _, n := range inputs {
+				if msg := validateInputValue(n, s.values[nodeID(n)]); msg != "" {
+					errs = append(errs, InputError{ID: nodeID(n), Message: msg})
+				}
+			}
+			if len(errs) > 0 {
+				return nil, errs
+			}
+			for _, n := range inputs {
+				data[nodeID(n)] = s.values[nodeID(n)]
+			}
+		}
+		res.Data = data
+	default:
+		return nil, fmt.Errorf("action %s is not supported", a.NodeType())
+	}
+	return res, nil
+}
+
+// toggle changes visibility of the target elements
+func (s *Simulator) toggle(t TargetElement) {
+	walkTree(s.card, "", func(path string, n Node) {
+		if n == nil || nodeID(n) != t.ElementID {
+			return
+		}
+		f := structField(n, "IsVisible")
+		if !f.IsValid() || !f.CanSet() {
+			return
+		}
+		visible := !isVisible(n)
+		if t.IsVisible != nil {
+			visible = *t.IsVisible
+		}
+		f.Set(reflect.ValueOf(&visible))
+	})
+}
+
+// walk calls fn for every element the user can see and use with the list of cards
+// the element belongs to, outermost first: nil for the card itself and Action.ShowCard actions
+func (s *Simulator) walk(fn func(n Node, scopes []Node)) {
+	var walk func(parent interface{}, scopes []Node)
+	walk = func(parent interface{}, scopes []Node) {
+		for _, child := range childNodes(parent) {
+			n := child.node
+			if child.path == "fallback" || isNilNode(n) || !isVisible(n) {
+				continue
+			}
+			fn(n, scopes)
+			if sc, ok := n.(*ActionShowCard); ok {
+				if s.expanded[sc] {
+					walk(sc, append(scopes[:len(scopes):len(scopes)], sc))
+				}
+				continue
+			}
+			walk(n, scopes)
+		}
+	}
+	walk(s.card, []Node{nil})
+}
+
+// scopeInputs returns inputs of the cards (nil is the card itself), inputs of nested Action.ShowCard cards aren't included
+func scopeInputs(c *Card, scopes []Node) []Node {
+	var res []Node
+	var collect func(parent interface{})
+	collect = func(parent interface{}) {
+		for _, child := range childNodes(parent) {
+			n := child.node
+			if child.path == "fallback" || isNilNode(n) {
+				continue
+			}
+			if isInput(n) {
+				res = append(res, n)
+			}
+			if _, ok := n.(*ActionShowCard); !ok {
+				collect(n)
+			}
+		}
+	}
+	for _, sc := range scopes {
+		if sc == nil {
+			collect(c)
+		} else {
+			collect(sc)
+		}
+	}
+	return res
+}
+
+// isInput tells if the element is one of the known inputs
+func isInput(n Node) bool {
+	switch n.(type) {
+	case *InputText, *InputNumber, *InputDate, *InputTime, *InputToggle, *InputChoiceSet:
+		return true
+	}
+	return false
+}
+
+// isVisible tells if the element isn't hidden with isVisible property
+func isVisible(n Node) bool {
+	v, _ := fieldValue(n, "IsVisible").(*bool)
+	return v == nil || *v
+}
+
+// initialValue returns value of the input before user changes it
+func initialValue(n Node) string {
+	switch n := n.(type) {
+	case *InputText:
+		return n.Value
+	case *InputNumber:
+		if n.Value != 0 {
+			return formatFloat(n.Value)
+		}
+	case *InputDate:
+		return n.Value
+	case *InputTime:
+		return n.Value
+	case *InputToggle:
+		on, off := toggleValues(n)
+		if n.Value == on {
+			return on
+		}
+		return off
+	case *InputChoiceSet:
+		return n.Value
+	}
+	return ""
+}
+
+func toggleValues(n *InputToggle) (string, string) {
+	return firstNonEmpty(n.ValueOn, "true"), firstNonEmpty(n.ValueOff, "false")
+}
+
+// inputValue converts the value to the string a client would send for the input
+func inputValue(n Node, value interface{}) (string, error) {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case bool:
+		t, ok := n.(*InputToggle)
+		if !ok {
+			return "", errors.New("bool value is only accepted by Input.Toggle")
+		}
+		on, off := toggleValues(t)
+		if v {
+			return on, nil
+		}
+		return off, nil
+	case int:
+		s = strconv.Itoa(v)
+	case float64:
+		s = formatFloat(v)
+	case []string:
+		c, ok := n.(*InputChoiceSet)
+		if !ok || !isTrue(c.IsMultiSelect) {
+			return "", errors.New("list value is only accepted by multi-select Input.ChoiceSet")
+		}
+		s = strings.Join(v, ",")
+	default:
+		return "", fmt.Errorf("unsupported value type %T", value)
+	}
+	if s == "" {
+		return s, nil
+	}
+	if msg := valueFormatProblem(n, s); msg != "" {
+		return "", errors.New(msg)
+	}
+	return s, nil
+}
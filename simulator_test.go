@@ -0,0 +1,183 @@
+package cards
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func simulatorCard() *Card {
+	return New([]Node{
+		&TextBlock{Text: "Order", Size: SizeLarge},
+		&TextBlock{Text: "Details", ID: "details", IsVisible: FalsePtr()},
+		&InputText{ID: "name", Label: "Name", IsRequired: TruePtr(), Regex: "^[A-Z]", MaxLength: 10, ErrorMessage: "Enter a name"},
+		&InputNumber{ID: "qty", Min: FloatPtr(1), Max: FloatPtr(10), Value: 1},
+		&InputChoiceSet{ID: "color", Choices: []*InputChoice{{Title: "Red", Value: "red"}, {Title: "Blue", Value: "blue"}}, IsMultiSelect: TruePtr()},
+		&InputToggle{ID: "agree", Title: "I agree", IsRequired: TruePtr()},
+	}, []Node{
+		&ActionToggleVisibility{Title: "Show details", TargetElements: []TargetElement{{ElementID: "details"}}},
+		&ActionSubmit{Title: "Send", Data: map[string]interface{}{"action": "send"}},
+		&ActionShowCard{Title: "Comment", Card: NestedCard{
+			Body:    []Node{&InputText{ID: "comment", IsRequired: TruePtr()}},
+			Actions: []Node{&ActionSubmit{ID: "post", Title: "Send"}},
+		}},
+	})
+}
+
+func TestSimulatorSubmit(t *testing.T) {
+	s, err := NewSimulator(simulatorCard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Click("Send")
+	var errs InputErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected input errors but got %v", err)
+	}
+	expected := InputErrors{
+		{ID: "name", Message: "Enter a name"},
+		{ID: "agree", Message: "toggle must be on"},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("expected %v but got %v", expected, errs)
+	}
+
+	for id, value := range map[string]interface{}{
+		"name":  "Bob",
+		"qty":   3,
+		"color": []string{"red", "blue"},
+		"agree": true,
+	} {
+		if err := s.Set(id, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res, err := s.Click("Send")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]interface{}{
+		"action": "send",
+		"name":   "Bob",
+		"qty":    "3",
+		"color":  "red,blue",
+		"agree":  "true",
+	}
+	if !reflect.DeepEqual(res.Data, data) {
+		t.Errorf("expected payload %v but got %v", data, res.Data)
+	}
+
+	if err := s.Set("name", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Click("Send"); err == nil || err.Error() != "invalid input: name: Enter a name" {
+		t.Errorf("expected regex error but got %v", err)
+	}
+}
+
+func TestSimulatorShowCard(t *testing.T) {
+	s, err := NewSimulator(simulatorCard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Click("post"); err == nil {
+		t.Error("expected action of collapsed card to be not found")
+	}
+	if err := s.Set("comment", "hi"); err == nil {
+		t.Error("expected input of collapsed card to be not found")
+	}
+	if _, err := s.Click("Comment"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsExpanded("Comment") {
+		t.Error("expected card to be expanded")
+	}
+	if _, err := s.Click("Send"); err == nil {
+		t.Error("expected ambiguous action error")
+	}
+	s.Set("name", "Bob")
+	s.Set("agree", "true")
+	s.Set("comment", "Looks good")
+	res, err := s.Click("post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]interface{}{
+		"name":    "Bob",
+		"qty":     "1",
+		"color":   "",
+		"agree":   "true",
+		"comment": "Looks good",
+	}
+	if !reflect.DeepEqual(res.Data, data) {
+		t.Errorf("expected payload %v but got %v", data, res.Data)
+	}
+}
+
+func TestSimulatorToggleVisibility(t *testing.T) {
+	c := simulatorCard()
+	s, err := NewSimulator(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.IsVisible("details") {
+		t.Error("expected details to be hidden")
+	}
+	if _, err := s.Click("Show details"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsVisible("details") {
+		t.Error("expected details to be visible")
+	}
+	if *s.Card().Body[1].(*TextBlock).IsVisible != true {
+		t.Error("expected simulated card to be changed")
+	}
+	if *c.Body[1].(*TextBlock).IsVisible != false {
+		t.Error("expected original card to be kept")
+	}
+	s.Click("Show details")
+	if s.IsVisible("details") {
+		t.Error("expected details to be hidden again")
+	}
+}
+
+func TestSimulatorSet(t *testing.T) {
+	s, err := NewSimulator(simulatorCard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		id    string
+		value interface{}
+	}{
+		{"qty", "many"},
+		{"color", "green"},
+		{"name", true},
+		{"agree", "yes"},
+		{"missing", "x"},
+	} {
+		if err := s.Set(tc.id, tc.value); err == nil {
+			t.Errorf("%s: expected error for %v", tc.id, tc.value)
+		}
+	}
+	if err := s.Set("qty", 20); err != nil {
+		t.Fatal(err)
+	}
+	s.Set("name", "Bob")
+	s.Set("agree", true)
+	if _, err := s.Click("Send"); err == nil || err.Error() != "invalid input: qty: value is greater than 10" {
+		t.Errorf("expected max error but got %v", err)
+	}
+}
+
+func TestSimulatorZeroLimit(t *testing.T) {
+	s, err := NewSimulator(New([]Node{&InputNumber{ID: "n", Min: FloatPtr(0)}}, []Node{&ActionSubmit{Title: "Send"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Set("n", -5)
+	_, err = s.Click("Send")
+	if !reflect.DeepEqual(err, InputErrors{{ID: "n", Message: "value is less than 0"}}) {
+		t.Errorf("expected min 0 to be enforced but got %v", err)
+	}
+}
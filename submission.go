@@ -0,0 +1,610 @@
+package cards
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// InputDateLayout is format of Input.Date values
+	InputDateLayout = "2006-01-02"
+	// InputTimeLayout is format of Input.Time values
+	InputTimeLayout = "15:04"
+)
+
+// TimeOfDay is a civil time value of Input.Time, e.g. 16:59
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+	Second int
+}
+
+// ParseTimeOfDay parses time in "15:04" or "15:04:05" format
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	layout := InputTimeLayout
+	if strings.Count(s, ":") == 2 {
+		layout = "15:04:05"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return TimeOfDay{}, fmt.Errorf("%q is not a time (HH:MM)", s)
+	}
+	return TimeOfDay{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second()}, nil
+}
+
+// String returns time in "15:04" format, seconds are added if they are set
+func (t TimeOfDay) String() string {
+	if t.Second != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	}
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+}
+
+// Duration returns time passed since midnight
+func (t TimeOfDay) Duration() time.Duration {
+	return time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute + time.Duration(t.Second)*time.Second
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (t *TimeOfDay) UnmarshalText(data []byte) error {
+	res, err := ParseTimeOfDay(string(data))
+	if err != nil {
+		return err
+	}
+	*t = res
+	return nil
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	timeOfDayType = reflect.TypeOf(TimeOfDay{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+)
+
+// DecodeSubmission decodes Action.Submit payload into v using input definitions of the card.
+// Input values which clients send as strings are converted: Input.Toggle to bool, multi-select
+// Input.ChoiceSet to []string, Input.Number to numbers, Input.Date to time.Time and Input.Time
+// to TimeOfDay, time.Duration or time.Time. Other payload fields (action data) are decoded as JSON.
+//
+// v is a pointer to a struct or to map[string]interface{}. Struct fields are matched by `card` tag,
+// then by `json` tag and field name like encoding/json does.
+func DecodeSubmission(c *Card, payload []byte, v interface{}) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return fmt.Errorf("invalid submission payload: %w", err)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("DecodeSubmission requires non-nil pointer")
+	}
+	inputs := cardInputs(c)
+	target := rv.Elem()
+	switch {
+	case target.Kind() == reflect.Struct:
+		return decodeSubmissionStruct(target, raw, inputs)
+	case target.Kind() == reflect.Map && target.Type().Key().Kind() == reflect.String:
+		if target.IsNil() {
+			target.Set(reflect.MakeMap(target.Type()))
+		}
+		for key, value := range raw {
+			elem := reflect.New(target.Type().Elem()).Elem()
+			if err := decodeSubmissionValue(elem, inputs[key], value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			target.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), elem)
+		}
+		return nil
+	}
+	return fmt.Errorf("can't decode submission into %s", target.Type())
+}
+
+// cardInputs returns inputs of the card (including Action.ShowCard cards) by ID
+func cardInputs(c *Card) map[string]Node {
+	inputs := map[string]Node{}
+	walkTree(c, "", func(path string, n Node) {
+		if isNilNode(n) || !isInput(n) {
+			return
+		}
+		if _, ok := inputs[nodeID(n)]; !ok {
+			inputs[nodeID(n)] = n
+		}
+	})
+	return inputs
+}
+
+func decodeSubmissionStruct(target reflect.Value, raw map[string]interface{}, inputs map[string]Node) error {
+	t := target.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		key, ok := submissionKey(field)
+		if !ok {
+			continue
+		}
+		if key == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := decodeSubmissionStruct(target.Field(i), raw, inputs); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if key == "" {
+			key = field.Name
+		}
+		value, ok := raw[key]
+		if !ok {
+			// like encoding/json field names are matched case-insensitively
+			for k, v := range raw {
+				if strings.EqualFold(k, key) {
+					key, value, ok = k, v, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		if err := decodeSubmissionValue(target.Field(i), inputs[key], value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// submissionKey returns payload key of the field from `card` or `json` tag, false means the field is skipped
+func submissionKey(field reflect.StructField) (string, bool) {
+	for _, tag := range []string{"card", "json"} {
+		name, ok := field.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		name = strings.Split(name, ",")[0]
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	return "", true
+}
+
+// decodeSubmissionValue sets f to the payload value, input string values are converted according to the input
+func decodeSubmissionValue(f reflect.Value, input Node, value interface{}) error {
+	s, isString := value.(string)
+	if input == nil || !isString {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, f.Addr().Interface())
+	}
+	switch {
+	case f.Kind() == reflect.Ptr:
+		if s == "" {
+			f.Set(reflect.Zero(f.Type()))
+			return nil
+		}
+		p := reflect.New(f.Type().Elem())
+		if err := decodeSubmissionValue(p.Elem(), input, s); err != nil {
+			return err
+		}
+		f.Set(p)
+		return nil
+	case f.Kind() == reflect.Interface && f.NumMethod() == 0:
+		v, err := inputNativeValue(input, s)
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			f.Set(reflect.ValueOf(v))
+		}
+		return nil
+	case f.Type() == timeType:
+		if s == "" {
+			return nil
+		}
+		layout := InputDateLayout
+		if _, ok := input.(*InputTime); ok {
+			layout = InputTimeLayout
+		}
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return fmt.Errorf("%q is not a valid %s value", s, input.NodeType())
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	case f.Type() == timeOfDayType || f.Type() == durationType:
+		if s == "" {
+			return nil
+		}
+		t, err := ParseTimeOfDay(s)
+		if err != nil {
+			return err
+		}
+		if f.Type() == durationType {
+			f.SetInt(int64(t.Duration()))
+		} else {
+			f.Set(reflect.ValueOf(t))
+		}
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Bool:
+		b, err := inputBool(input, s)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Slice:
+		if f.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("can't decode %s value into %s", input.NodeType(), f.Type())
+		}
+		values := splitChoices(s)
+		res := reflect.MakeSlice(f.Type(), len(values), len(values))
+		for i, v := range values {
+			res.Index(i).SetString(v)
+		}
+		f.Set(res)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if s == "" {
+			return nil
+		}
+		n, err := parseNumber(s)
+		if err != nil {
+			return err
+		}
+		return setNumber(f, n)
+	default:
+		return fmt.Errorf("can't decode %s value into %s", input.NodeType(), f.Type())
+	}
+	return nil
+}
+
+func setNumber(f reflect.Value, n float64) error {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return fmt.Errorf("%s is not a number", formatFloat(n))
+	}
+	switch f.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if f.OverflowFloat(n) {
+			return fmt.Errorf("%s overflows %s", formatFloat(n), f.Type())
+		}
+		f.SetFloat(n)
+		return nil
+	}
+	if n != math.Trunc(n) {
+		return fmt.Errorf("%s is not an integer", formatFloat(n))
+	}
+	// bounds are compared as floats, converting out of range float is undefined
+	bits := f.Type().Bits()
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n < -math.Ldexp(1, bits-1) || n >= math.Ldexp(1, bits-1) {
+			return fmt.Errorf("%s overflows %s", formatFloat(n), f.Type())
+		}
+		f.SetInt(int64(n))
+	default:
+		if n < 0 || n >= math.Ldexp(1, bits) {
+			return fmt.Errorf("%s overflows %s", formatFloat(n), f.Type())
+		}
+		f.SetUint(uint64(n))
+	}
+	return nil
+}
+
+// inputNativeValue converts input string value into its natural Go type,
+// nil means that value is empty
+func inputNativeValue(input Node, s string) (interface{}, error) {
+	switch n := input.(type) {
+	case *InputToggle:
+		return inputBool(n, s)
+	case *InputChoiceSet:
+		if isTrue(n.IsMultiSelect) {
+			return splitChoices(s), nil
+		}
+		return s, nil
+	case *InputText:
+		return s, nil
+	}
+	if s == "" {
+		return nil, nil
+	}
+	switch input.(type) {
+	case *InputNumber:
+		return parseNumber(s)
+	case *InputDate:
+		t, err := time.Parse(InputDateLayout, s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a date (YYYY-MM-DD)", s)
+		}
+		return t, nil
+	case *InputTime:
+		return ParseTimeOfDay(s)
+	}
+	return s, nil
+}
+
+// inputBool converts toggle value (valueOn or valueOff) or boolean string to bool
+func inputBool(input Node, s string) (bool, error) {
+	if t, ok := input.(*InputToggle); ok {
+		on, off := toggleValues(t)
+		switch s {
+		case on:
+			return true, nil
+		case off, "":
+			return false, nil
+		}
+		return false, fmt.Errorf("toggle value must be %q or %q", on, off)
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("%q is not a boolean", s)
+	}
+	return b, nil
+}
+
+// splitChoices splits comma-joined values of multi-select Input.ChoiceSet
+func splitChoices(s string) []string {
+	res := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// SubmissionErrors holds problems of submitted input values by input ID
+type SubmissionErrors map[string]string
+
+func (e SubmissionErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", id, e[id]))
+	}
+	return "invalid submission: " + strings.Join(msgs, "; ")
+}
+
+// Apply sets ErrorMessage of the card inputs to the problems, so the card can be sent back to the user
+func (e SubmissionErrors) Apply(c *Card) {
+	walkTree(c, "", func(path string, n Node) {
+		if isNilNode(n) || !isInput(n) {
+			return
+		}
+		if msg, ok := e[nodeID(n)]; ok {
+			if f := structField(n, "ErrorMessage"); f.IsValid() && f.CanSet() {
+				f.SetString(msg)
+			}
+		}
+	})
+}
+
+// ValidateSubmission checks Action.Submit payload against input constraints of the card like a client does:
+// isRequired, regex and maxLength of Input.Text, min and max of Input.Number, Input.Date and Input.Time,
+// values of Input.Toggle and Input.ChoiceSet. Clients can be bypassed, so submissions are to be checked on the server.
+//
+// Inputs missing in the payload are reported as required only if they are outside Action.ShowCard cards,
+// because inputs of show cards are not sent with other actions. It returns nil if the payload is valid
+// and error if it isn't a JSON object.
+func ValidateSubmission(c *Card, payload []byte) (SubmissionErrors, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("invalid submission payload: %w", err)
+	}
+	errs := SubmissionErrors{}
+	check := func(n Node, inShowCard bool) {
+		id := nodeID(n)
+		value, ok := raw[id]
+		if !ok && inShowCard {
+			return
+		}
+		if msg := inputProblem(n, submittedString(value)); msg != "" {
+			errs[id] = msg
+		}
+	}
+	var walk func(parent interface{}, inShowCard bool)
+	walk = func(parent interface{}, inShowCard bool) {
+		for _, child := range childNodes(parent) {
+			n := child.node
+			if child.path == "fallback" || isNilNode(n) {
+				continue
+			}
+			if isInput(n) {
+				check(n, inShowCard)
+			}
+			_, sc := n.(*ActionShowCard)
+			walk(n, inShowCard || sc)
+		}
+	}
+	walk(c, false)
+	if len(errs) == 0 {
+		return nil, nil
+	}
+	return errs, nil
+}
+
+// submittedString returns payload value as a string the way clients send values
+func submittedString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return formatFloat(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, submittedString(item))
+		}
+		return strings.Join(values, ",")
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// validateInputValue checks the value like a client does on submit and returns error message
+// (input errorMessage if it is set) or empty string if the value is valid
+func validateInputValue(n Node, value string) string {
+	msg := inputProblem(n, value)
+	if msg == "" {
+		return ""
+	}
+	return firstNonEmpty(fieldString(n, "ErrorMessage"), msg)
+}
+
+// inputProblem returns what is wrong with the input value or empty string if it is valid
+func inputProblem(n Node, value string) string {
+	required := false
+	if r, _ := fieldValue(n, "IsRequired").(*bool); r != nil {
+		required = *r
+	}
+	if t, ok := n.(*InputToggle); ok && value == "" {
+		// missing toggle value means it is off
+		_, value = toggleValues(t)
+	}
+	if value == "" {
+		if required {
+			return "value is required"
+		}
+		return ""
+	}
+	if msg := valueFormatProblem(n, value); msg != "" {
+		return msg
+	}
+	switch n := n.(type) {
+	case *InputToggle:
+		if on, _ := toggleValues(n); required && value != on {
+			return "toggle must be on"
+		}
+	case *InputText:
+		if n.MaxLength > 0 && int64(utf8.RuneCountInString(value)) > n.MaxLength {
+			return fmt.Sprintf("value is longer than %d characters", n.MaxLength)
+		}
+		if n.Regex != "" {
+			re, err := regexp.Compile(n.Regex)
+			if err != nil {
+				return fmt.Sprintf("invalid regex %q", n.Regex)
+			}
+			if !re.MatchString(value) {
+				return fmt.Sprintf("value doesn't match %q", n.Regex)
+			}
+		}
+	case *InputNumber:
+		f, _ := parseNumber(value)
+		switch {
+		case n.Min != nil && f < *n.Min:
+			return fmt.Sprintf("value is less than %s", formatFloat(*n.Min))
+		case n.Max != nil && f > *n.Max:
+			return fmt.Sprintf("value is greater than %s", formatFloat(*n.Max))
+		}
+	case *InputDate:
+		return rangeProblem(value, n.Min, n.Max)
+	case *InputTime:
+		if t, err := ParseTimeOfDay(value); err == nil {
+			value = t.String()
+		}
+		return rangeProblem(value, n.Min, n.Max)
+	}
+	return ""
+}
+
+// numberRegexp matches numbers as clients send them, see numberPattern
+var numberRegexp = regexp.MustCompile("^" + numberPattern + "$")
+
+// parseNumber parses Input.Number value. Unlike strconv.ParseFloat it only accepts
+// decimal notation: NaN, infinities and hex numbers are rejected.
+func parseNumber(s string) (float64, error) {
+	if !numberRegexp.MatchString(s) {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%q is out of range", s)
+	}
+	return f, nil
+}
+
+// valueFormatProblem checks that non-empty value could be sent by the input:
+// numbers, dates and times are well formed, toggle and choice values are declared
+func valueFormatProblem(n Node, s string) string {
+	switch n := n.(type) {
+	case *InputNumber:
+		if _, err := parseNumber(s); err != nil {
+			return err.Error()
+		}
+	case *InputDate:
+		if _, err := time.Parse(InputDateLayout, s); err != nil {
+			return fmt.Sprintf("%q is not a date (YYYY-MM-DD)", s)
+		}
+	case *InputTime:
+		if _, err := ParseTimeOfDay(s); err != nil {
+			return err.Error()
+		}
+	case *InputToggle:
+		if on, off := toggleValues(n); s != on && s != off {
+			return fmt.Sprintf("toggle value must be %q or %q", on, off)
+		}
+	case *InputChoiceSet:
+		values := strings.Split(s, ",")
+		if len(values) > 1 && !isTrue(n.IsMultiSelect) {
+			return "only one choice can be selected"
+		}
+		for _, v := range values {
+			if !hasChoice(n, strings.TrimSpace(v)) {
+				return fmt.Sprintf("%q is not one of the choices", v)
+			}
+		}
+	}
+	return ""
+}
+
+func hasChoice(n *InputChoiceSet, value string) bool {
+	for _, c := range n.Choices {
+		if c != nil && c.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeProblem checks ISO date or time against min and max which compare as strings
+func rangeProblem(value, min, max string) string {
+	switch {
+	case min != "" && value < min:
+		return fmt.Sprintf("value is before %s", min)
+	case max != "" && value > max:
+		return fmt.Sprintf("value is after %s", max)
+	}
+	return ""
+}
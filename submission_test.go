@@ -0,0 +1,223 @@
+package cards
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func submissionCard() *Card {
+	return New([]Node{
+		&InputText{ID: "name"},
+		&InputNumber{ID: "qty"},
+		&InputDate{ID: "due"},
+		&InputTime{ID: "at"},
+		&InputToggle{ID: "agree", Title: "I agree", ValueOn: "yes", ValueOff: "no"},
+		&InputChoiceSet{ID: "colors", IsMultiSelect: TruePtr(), Choices: []*InputChoice{
+			{Title: "Red", Value: "red"}, {Title: "Blue", Value: "blue"},
+		}},
+		&InputChoiceSet{ID: "size", Choices: []*InputChoice{{Title: "S", Value: "s"}, {Title: "M", Value: "m"}}},
+	}, []Node{
+		&ActionSubmit{Title: "Send", Data: map[string]interface{}{"action": "order", "orderId": 42}},
+	})
+}
+
+const submissionPayload = `{
+	"action": "order",
+	"orderId": 42,
+	"name": "Bob",
+	"qty": "3",
+	"due": "2021-03-04",
+	"at": "16:30",
+	"agree": "yes",
+	"colors": "red,blue",
+	"size": "m"
+}`
+
+func TestDecodeSubmission(t *testing.T) {
+	type order struct {
+		Action  string        `json:"action"`
+		OrderID int           `json:"orderId"`
+		Name    string        `card:"name"`
+		Qty     int           `json:"qty"`
+		Due     time.Time     `json:"due"`
+		At      TimeOfDay     `json:"at"`
+		Agree   bool          `json:"agree"`
+		Colors  []string      `json:"colors"`
+		Size    *string       `json:"size"`
+		Missing string        `json:"missing"`
+		Skipped string        `json:"-"`
+		AtDelay time.Duration `card:"at" json:"delay"`
+	}
+	var got order
+	if err := DecodeSubmission(submissionCard(), []byte(submissionPayload), &got); err != nil {
+		t.Fatal(err)
+	}
+	size := "m"
+	expected := order{
+		Action:  "order",
+		OrderID: 42,
+		Name:    "Bob",
+		Qty:     3,
+		Due:     time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+		At:      TimeOfDay{Hour: 16, Minute: 30},
+		Agree:   true,
+		Colors:  []string{"red", "blue"},
+		Size:    &size,
+		AtDelay: 16*time.Hour + 30*time.Minute,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v but got %+v", expected, got)
+	}
+}
+
+func TestDecodeSubmissionMap(t *testing.T) {
+	got := map[string]interface{}{}
+	if err := DecodeSubmission(submissionCard(), []byte(submissionPayload), &got); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"action":  "order",
+		"orderId": float64(42),
+		"name":    "Bob",
+		"qty":     float64(3),
+		"due":     time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+		"at":      TimeOfDay{Hour: 16, Minute: 30},
+		"agree":   true,
+		"colors":  []string{"red", "blue"},
+		"size":    "m",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v but got %v", expected, got)
+	}
+}
+
+func TestDecodeSubmissionErrors(t *testing.T) {
+	for payload, v := range map[string]interface{}{
+		`{"qty": "many"}`:   &struct{ Qty float64 }{},
+		`{"qty": "1.5"}`:    &struct{ Qty int }{},
+		`{"qty": "1e19"}`:   &struct{ Qty int64 }{},
+		`{"qty": "-1e300"}`: &struct{ Qty int64 }{},
+		`{"qty": "Inf"}`:    &struct{ Qty int64 }{},
+		`{"qty": "NaN"}`:    &struct{ Qty float64 }{},
+		`{"qty": "256"}`:    &struct{ Qty uint8 }{},
+		`{"qty": "-129"}`:   &struct{ Qty int8 }{},
+		`{"qty": "1e39"}`:   &struct{ Qty float32 }{},
+		`{"agree": "yup"}`:  &struct{ Agree bool }{},
+		`{"due": "04.03"}`:  &struct{ Due time.Time }{},
+		`{"at": "noon"}`:    &struct{ At TimeOfDay }{},
+		`{"name": "Bob"}`:   &struct{ Name []int }{},
+		`[1, 2]`:            &map[string]interface{}{},
+	} {
+		if err := DecodeSubmission(submissionCard(), []byte(payload), v); err == nil {
+			t.Errorf("%s: expected error", payload)
+		}
+	}
+	if err := DecodeSubmission(submissionCard(), []byte(`{}`), struct{}{}); err == nil {
+		t.Error("expected error for non-pointer")
+	}
+}
+
+func TestValidateSubmission(t *testing.T) {
+	c := New([]Node{
+		&InputText{ID: "name", IsRequired: TruePtr(), Regex: "^[A-Z]", MaxLength: 5},
+		&InputText{ID: "code", MaxLength: 3},
+		&InputNumber{ID: "qty", Min: FloatPtr(1), Max: FloatPtr(10)},
+		&InputDate{ID: "due", Min: "2021-01-01", Max: "2021-12-31"},
+		&InputTime{ID: "at", Min: "09:00", Max: "18:00"},
+		&InputToggle{ID: "agree", Title: "I agree", IsRequired: TruePtr()},
+		&InputChoiceSet{ID: "colors", IsMultiSelect: TruePtr(), Choices: []*InputChoice{
+			{Title: "Red", Value: "red"}, {Title: "Blue", Value: "blue"},
+		}},
+		&InputChoiceSet{ID: "size", IsRequired: TruePtr(), Choices: []*InputChoice{{Title: "S", Value: "s"}}},
+	}, []Node{
+		&ActionShowCard{Title: "Comment", Card: NestedCard{
+			Body: []Node{&InputText{ID: "comment", IsRequired: TruePtr()}},
+		}},
+	})
+
+	errs, err := ValidateSubmission(c, []byte(`{
+		"name": "bob",
+		"code": "abcd",
+		"qty": "11",
+		"due": "2022-01-01",
+		"at": "8:00",
+		"colors": "red,green"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := SubmissionErrors{
+		"name":   `value doesn't match "^[A-Z]"`,
+		"code":   "value is longer than 3 characters",
+		"qty":    "value is greater than 10",
+		"due":    "value is after 2021-12-31",
+		"at":     "value is before 09:00",
+		"agree":  "toggle must be on",
+		"colors": `"green" is not one of the choices`,
+		"size":   "value is required",
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("expected %v but got %v", expected, errs)
+	}
+
+	errs.Apply(c)
+	if msg := c.Body[7].(*InputChoiceSet).ErrorMessage; msg != "value is required" {
+		t.Errorf("expected error message to be applied but got %q", msg)
+	}
+
+	errs, err = ValidateSubmission(c, []byte(`{"name": "Bob", "qty": 2, "agree": "true", "colors": "blue,red", "size": "s", "comment": "hi"}`))
+	if err != nil || errs != nil {
+		t.Errorf("expected valid submission but got %v, %v", errs, err)
+	}
+	errs, _ = ValidateSubmission(c, []byte(`{"name": "Bob", "agree": "true", "size": "s,s", "comment": ""}`))
+	expected = SubmissionErrors{
+		"size":    "only one choice can be selected",
+		"comment": "value is required",
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("expected %v but got %v", expected, errs)
+	}
+	if _, err := ValidateSubmission(c, []byte(`"text"`)); err == nil {
+		t.Error("expected payload error")
+	}
+}
+
+func TestValidateSubmissionZeroLimits(t *testing.T) {
+	c := New([]Node{
+		&InputNumber{ID: "min", Min: FloatPtr(0)},
+		&InputNumber{ID: "max", Max: FloatPtr(0)},
+	}, nil)
+	errs, err := ValidateSubmission(c, []byte(`{"min": "-5", "max": "5"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := SubmissionErrors{
+		"min": "value is less than 0",
+		"max": "value is greater than 0",
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("expected %v but got %v", expected, errs)
+	}
+	if errs, _ := ValidateSubmission(c, []byte(`{"min": "0", "max": "0"}`)); errs != nil {
+		t.Errorf("expected limits to be inclusive but got %v", errs)
+	}
+}
+
+func TestValidateSubmissionNumbers(t *testing.T) {
+	c := New([]Node{&InputNumber{ID: "qty", Min: FloatPtr(1), Max: FloatPtr(10)}}, nil)
+	for _, qty := range []string{"NaN", "nan", "Inf", "-Infinity", "0x1p2", "1_0", "1e400", " 5", "+5"} {
+		errs, err := ValidateSubmission(c, []byte(`{"qty": "`+qty+`"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if errs["qty"] == "" {
+			t.Errorf("%q: expected number to be rejected", qty)
+		}
+	}
+	for _, qty := range []string{"5", "1.5", "10.", ".5e1", "1E1"} {
+		if errs, _ := ValidateSubmission(c, []byte(`{"qty": "`+qty+`"}`)); errs != nil {
+			t.Errorf("%q: expected number to be valid but got %v", qty, errs)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package cards
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxFallbackText is default length limit of generated fallback text
+	DefaultMaxFallbackText = 200
+	// DefaultMaxSpeak is default length limit of generated speak text without SSML markup
+	DefaultMaxSpeak = 500
+)
+
+// Summarizer derives FallbackText and Speak of the card from its body:
+// the first heading (or text if there is no heading), facts and highlighted or bolder text runs.
+// Hidden elements, fallbacks and actions are not used.
+type Summarizer struct {
+	MaxFallbackText int // 0 means DefaultMaxFallbackText
+	MaxSpeak        int // 0 means DefaultMaxSpeak
+}
+
+// WithAutoSummary makes Bytes, String etc fill empty FallbackText and Speak with generated summary in the JSON,
+// the card fields stay empty
+func (c *Card) WithAutoSummary() *Card {
+	c.Summarizer = &Summarizer{}
+	return c
+}
+
+// Summarize returns fallback text and SSML speak string for the card
+func (s *Summarizer) Summarize(c *Card) (fallbackText, speak string) {
+	parts := summaryParts(c)
+	return s.fallbackText(parts), s.speak(parts)
+}
+
+// Apply fills FallbackText and Speak of the card if they are empty
+func (s *Summarizer) Apply(c *Card) {
+	if c.FallbackText != "" && c.Speak != "" {
+		return
+	}
+	fallbackText, speak := s.Summarize(c)
+	if c.FallbackText == "" {
+		c.FallbackText = fallbackText
+	}
+	if c.Speak == "" {
+		c.Speak = speak
+	}
+}
+
+func (s *Summarizer) fallbackText(parts []string) string {
+	max := s.MaxFallbackText
+	if max <= 0 {
+		max = DefaultMaxFallbackText
+	}
+	sentences := make([]string, 0, len(parts))
+	for _, p := range parts {
+		sentences = append(sentences, sentence(p))
+	}
+	return truncateText(strings.Join(sentences, " "), max)
+}
+
+func (s *Summarizer) speak(parts []string) string {
+	if len(parts) == 0 {
+		return ""
+	}
+	left := s.MaxSpeak
+	if left <= 0 {
+		left = DefaultMaxSpeak
+	}
+	var b strings.Builder
+	b.WriteString("<speak>")
+	for _, p := range parts {
+		p = truncateText(sentence(p), left)
+		if p == "" {
+			break
+		}
+		b.WriteString("<s>" + html.EscapeString(p) + "</s>")
+		left -= utf8.RuneCountInString(p)
+	}
+	b.WriteString("</speak>")
+	return b.String()
+}
+
+// summaryParts returns plain text pieces the summary consists of
+func summaryParts(c *Card) []string {
+	var heading, text string
+	var facts, runs []string
+	var collect func(nodes []Node)
+	collect = func(nodes []Node) {
+		for _, n := range nodes {
+			if isNilNode(n) {
+				continue
+			}
+			if visible, _ := fieldValue(n, "IsVisible").(*bool); visible != nil && !*visible {
+				continue
+			}
+			switch n := n.(type) {
+			case *TextBlock:
+				t := plainText(n.Text)
+				switch {
+				case t == "":
+				case heading == "" && (headingLevel(n.Size, n.Weight) > 0 || strings.EqualFold(string(n.Weight), string(WeightBolder))):
+					heading = t
+				case text == "":
+					text = t
+				}
+			case *RichTextBlock:
+				var all []string
+				for _, run := range n.Inlines {
+					if run == nil {
+						continue
+					}
+					if isTrue(run.Highlight) || strings.EqualFold(string(run.Weight), string(WeightBolder)) {
+						if t := plainText(run.Text); t != "" {
+							runs = append(runs, t)
+						}
+					}
+					all = append(all, run.Text)
+				}
+				if text == "" {
+					text = plainText(strings.Join(all, ""))
+				}
+			case *FactSet:
+				for _, f := range n.Facts {
+					if f != nil {
+						facts = append(facts, strings.TrimSuffix(strings.TrimSpace(f.Title), ":")+": "+plainText(f.Value))
+					}
+				}
+			case *Container:
+				collect(n.Items)
+			case *ColumnSet:
+				for _, col := range n.Columns {
+					if col != nil && (col.IsVisible == nil || *col.IsVisible) {
+						collect(col.Items)
+					}
+				}
+			}
+		}
+	}
+	collect(c.Body)
+
+	var parts []string
+	if heading == "" {
+		heading = text
+	}
+	if heading != "" {
+		parts = append(parts, heading)
+	}
+	if len(facts) > 0 {
+		parts = append(parts, strings.Join(facts, ", "))
+	}
+	return append(parts, runs...)
+}
+
+// plainText strips markdown and collapses whitespace
+func plainText(s string) string {
+	return strings.Join(strings.Fields(markdownText(s)), " ")
+}
+
+// sentence adds full stop to the text if it doesn't end with punctuation
+func sentence(s string) string {
+	if r, _ := utf8.DecodeLastRuneInString(s); r != utf8.RuneError && !unicode.IsPunct(r) {
+		return s + "."
+	}
+	return s
+}
+
+// truncateText cuts the text at word boundary so it fits max runes including ellipsis
+func truncateText(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	if max <= 1 {
+		return ""
+	}
+	cut := string([]rune(s)[:max-1])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) }) + "…"
+}
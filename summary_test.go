@@ -0,0 +1,89 @@
+package cards
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	c, err := Parse(strings.NewReader(mustReadFile("./test/example.json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Summarizer{}
+	fallbackText, speak := s.Summarize(c)
+	expected := "Publish Adaptive Card schema. Board: Adaptive Card, List: Backlog, Assigned to: Matt Hidinger, Due date: Not set."
+	if fallbackText != expected {
+		t.Errorf("expected fallback text %q but got %q", expected, fallbackText)
+	}
+	expected = "<speak><s>Publish Adaptive Card schema.</s><s>Board: Adaptive Card, List: Backlog, Assigned to: Matt Hidinger, Due date: Not set.</s></speak>"
+	if speak != expected {
+		t.Errorf("expected speak %q but got %q", expected, speak)
+	}
+}
+
+func TestSummarizeLimits(t *testing.T) {
+	c := New([]Node{
+		&TextBlock{Text: "Secret", Size: SizeLarge, IsVisible: FalsePtr()},
+		&TextBlock{Text: "Build **failed** on master & release"},
+		&RichTextBlock{Inlines: []*TextRun{
+			{Text: "Duration "},
+			{Text: "12 minutes", Weight: WeightBolder},
+			{Text: " and "},
+			{Text: "3 tests failed", Highlight: TruePtr()},
+		}},
+	}, nil)
+	s := &Summarizer{MaxFallbackText: 40, MaxSpeak: 53}
+	fallbackText, speak := s.Summarize(c)
+	expected := "Build failed on master & release. 12…"
+	if fallbackText != expected {
+		t.Errorf("expected fallback text %q but got %q", expected, fallbackText)
+	}
+	expected = "<speak><s>Build failed on master &amp; release.</s><s>12 minutes.</s><s>3 tests…</s></speak>"
+	if speak != expected {
+		t.Errorf("expected speak %q but got %q", expected, speak)
+	}
+}
+
+func TestAutoSummary(t *testing.T) {
+	c := New([]Node{
+		&TextBlock{Text: "Deploy finished", Size: SizeMedium, Weight: WeightBolder},
+	}, nil).WithAutoSummary()
+	c.FallbackText = "Deployed"
+	data, err := c.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["fallbackText"] != "Deployed" {
+		t.Errorf("expected fallback text to be kept but got %v", got["fallbackText"])
+	}
+	if got["speak"] != "<speak><s>Deploy finished.</s></speak>" {
+		t.Errorf("expected generated speak but got %v", got["speak"])
+	}
+	if _, ok := got["Summarizer"]; ok {
+		t.Error("summarizer must not be serialized")
+	}
+}
+
+func TestAutoSummaryDoesNotModifyCard(t *testing.T) {
+	c := New([]Node{&TextBlock{Text: "Deploy started"}}, nil).WithAutoSummary()
+	if _, err := c.Bytes(); err != nil {
+		t.Fatal(err)
+	}
+	if c.FallbackText != "" || c.Speak != "" {
+		t.Errorf("expected summary to be generated only in JSON, got %q %q", c.FallbackText, c.Speak)
+	}
+	c.Body[0].(*TextBlock).Text = "Deploy finished"
+	got, err := c.String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, `"fallbackText":"Deploy finished."`) {
+		t.Errorf("expected summary of the changed card, got %s", got)
+	}
+}
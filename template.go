@@ -0,0 +1,468 @@
+package cards
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	templateDataKey = "$data"
+	templateWhenKey = "$when"
+	templateRootKey = "$root"
+	templateHostKey = "$host"
+	templateIndex   = "$index"
+)
+
+// Template is compiled adaptive card template.
+// Bindings and expressions are parsed once by CompileTemplate,
+// so a template can be expanded many times with different data.
+// Template is immutable and safe for concurrent use.
+type Template struct {
+	root templateNode
+}
+
+// CompileTemplate parses adaptive card template JSON with all its bindings.
+// Templating follows Adaptive Cards Templating spec (https://docs.microsoft.com/en-us/adaptive-cards/templating/language):
+// ${...} bindings in strings, $data repetition and scoping, $when conditions and $root/$index/$host scopes.
+func CompileTemplate(template []byte) (*Template, error) {
+	var tmpl interface{}
+	if err := json.Unmarshal(template, &tmpl); err != nil {
+		return nil, fmt.Errorf("template is not valid JSON: %w", err)
+	}
+	root, err := compileTemplateValue(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{root: root}, nil
+}
+
+// MustCompileTemplate is like CompileTemplate but panics on error
+func MustCompileTemplate(template []byte) *Template {
+	t, err := CompileTemplate(template)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Expand expands template with provided data. Data can be any value serializable to JSON.
+// Resulting card is prepared.
+func (t *Template) Expand(data interface{}) (*Card, error) {
+	return t.ExpandWithHost(data, nil)
+}
+
+// ExpandWithHost is like Expand but also provides host data available as $host
+func (t *Template) ExpandWithHost(data interface{}, host interface{}) (*Card, error) {
+	expanded, err := t.expand(data, host)
+	if err != nil {
+		return nil, err
+	}
+	var c Card
+	if err := decodeTree(expanded, &c); err != nil {
+		return nil, fmt.Errorf("expanded template is not a valid card: %w", err)
+	}
+	if err := c.Prepare(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ExpandJSON expands template with provided data and writes resulting card JSON to w.
+// The card is written as expanded without decoding and preparing it.
+func (t *Template) ExpandJSON(w io.Writer, data interface{}) error {
+	expanded, err := t.expand(data, nil)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(expanded)
+}
+
+// expand returns expanded template tree
+func (t *Template) expand(data interface{}, host interface{}) (interface{}, error) {
+	root, err := normalizeData(data)
+	if err != nil {
+		return nil, fmt.Errorf("data: %w", err)
+	}
+	hostData, err := normalizeData(host)
+	if err != nil {
+		return nil, fmt.Errorf("host: %w", err)
+	}
+	s := &templateScope{data: root, root: root, host: hostData}
+	if obj, ok := t.root.(*templateObject); ok && obj.data != nil {
+		if s, err = obj.scope(s); err != nil {
+			return nil, err
+		}
+	}
+	expanded, keep, err := t.root.expand(s)
+	if err != nil {
+		return nil, err
+	}
+	if !keep {
+		return nil, fmt.Errorf("card is removed by %s", templateWhenKey)
+	}
+	return expanded, nil
+}
+
+// ExpandTemplate compiles and expands adaptive card template JSON with provided data.
+// Use CompileTemplate to expand the same template many times.
+func ExpandTemplate(template []byte, data interface{}) (*Card, error) {
+	return ExpandTemplateWithHost(template, data, nil)
+}
+
+// ExpandTemplateWithHost is like ExpandTemplate but also provides host data available as $host
+func ExpandTemplateWithHost(template []byte, data interface{}, host interface{}) (*Card, error) {
+	t, err := CompileTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	return t.ExpandWithHost(data, host)
+}
+
+// Expand uses the card as template and expands its string bindings with provided data.
+// Card structs have no $data and $when fields, use ExpandTemplate to get full templating features.
+// The card itself is not modified.
+func (c *Card) Expand(data interface{}) (*Card, error) {
+	// Bytes prepares the card, the copy keeps the card as is
+	template, err := c.Clone().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return ExpandTemplate(template, data)
+}
+
+// templateScope is data context available to bindings
+type templateScope struct {
+	data     interface{}
+	root     interface{}
+	host     interface{}
+	index    int
+	hasIndex bool
+}
+
+func (s *templateScope) with(data interface{}) *templateScope {
+	return &templateScope{data: data, root: s.root, host: s.host}
+}
+
+func (s *templateScope) withIndex(data interface{}, i int) *templateScope {
+	return &templateScope{data: data, root: s.root, host: s.host, index: i, hasIndex: true}
+}
+
+// lookup resolves $data, $root, $index, $host and properties of current data
+func (s *templateScope) lookup(name string) (interface{}, bool) {
+	switch name {
+	case templateDataKey:
+		return s.data, true
+	case templateRootKey:
+		return s.root, true
+	case templateHostKey:
+		return s.host, true
+	case templateIndex:
+		if !s.hasIndex {
+			return nil, false
+		}
+		return float64(s.index), true
+	}
+	return property(s.data, name)
+}
+
+// templateNode is compiled template value.
+// Keep is false if the value has to be removed from the result because of $when.
+type templateNode interface {
+	expand(s *templateScope) (v interface{}, keep bool, err error)
+}
+
+func compileTemplateValue(v interface{}) (templateNode, error) {
+	switch t := v.(type) {
+	case string:
+		return compileTemplateString(t)
+	case []interface{}:
+		arr := &templateArray{}
+		for i, item := range t {
+			n, err := compileTemplateValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			arr.items = append(arr.items, n)
+		}
+		return arr, nil
+	case map[string]interface{}:
+		return compileTemplateObject(t)
+	default:
+		return templateLiteral{value: v}, nil
+	}
+}
+
+// templateLiteral is a value without bindings
+type templateLiteral struct {
+	value interface{}
+}
+
+func (n templateLiteral) expand(s *templateScope) (interface{}, bool, error) {
+	return n.value, true, nil
+}
+
+// templateString is a string with ${...} bindings
+type templateString struct {
+	src   string
+	parts []templateStringPart
+}
+
+// templateStringPart is either literal text or a binding
+type templateStringPart struct {
+	text string
+	expr *Expression
+}
+
+func compileTemplateString(str string) (templateNode, error) {
+	bindings, err := findBindings(str)
+	if err != nil {
+		return nil, err
+	}
+	if len(bindings) == 0 {
+		return templateLiteral{value: str}, nil
+	}
+	n := &templateString{src: str}
+	last := 0
+	for _, b := range bindings {
+		if b.start > last {
+			n.parts = append(n.parts, templateStringPart{text: str[last:b.start]})
+		}
+		e, err := ParseExpression(b.expr)
+		if err != nil {
+			return nil, err
+		}
+		n.parts = append(n.parts, templateStringPart{text: str[b.start:b.end], expr: e})
+		last = b.end
+	}
+	if last < len(str) {
+		n.parts = append(n.parts, templateStringPart{text: str[last:]})
+	}
+	return n, nil
+}
+
+// expand replaces bindings in string.
+// If the whole string is a single binding, value keeps its type.
+// Bindings evaluating to null are left as is.
+func (n *templateString) expand(s *templateScope) (interface{}, bool, error) {
+	if len(n.parts) == 1 {
+		v, err := n.parts[0].expr.eval(s)
+		if err != nil {
+			return nil, false, err
+		}
+		if v == nil {
+			return n.src, true, nil
+		}
+		return v, true, nil
+	}
+	var b strings.Builder
+	for _, part := range n.parts {
+		if part.expr == nil {
+			b.WriteString(part.text)
+			continue
+		}
+		v, err := part.expr.eval(s)
+		if err != nil {
+			return nil, false, err
+		}
+		if v == nil {
+			b.WriteString(part.text)
+		} else {
+			b.WriteString(stringify(v))
+		}
+	}
+	return b.String(), true, nil
+}
+
+// templateArray expands its items repeating the ones with array $data
+type templateArray struct {
+	items []templateNode
+}
+
+func (n *templateArray) expand(s *templateScope) (interface{}, bool, error) {
+	res := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		obj, ok := item.(*templateObject)
+		if !ok || obj.data == nil {
+			expanded, keep, err := item.expand(s)
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				res = append(res, expanded)
+			}
+			continue
+		}
+		data, err := obj.evalData(s)
+		if err != nil {
+			return nil, false, err
+		}
+		items, isArray := data.([]interface{})
+		if !isArray {
+			expanded, keep, err := obj.expand(s.with(data))
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				res = append(res, expanded)
+			}
+			continue
+		}
+		// repeat element for every item of data array
+		for i, dataItem := range items {
+			expanded, keep, err := obj.expand(s.withIndex(dataItem, i))
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				res = append(res, expanded)
+			}
+		}
+	}
+	return res, true, nil
+}
+
+// templateObject is an object with optional $data and $when.
+// Scope established by $data is applied by the parent: an array repeats
+// the object, a property value just uses the data as its scope.
+type templateObject struct {
+	data   templateNode
+	when   templateNode
+	keys   []string
+	values []templateNode
+}
+
+func compileTemplateObject(obj map[string]interface{}) (templateNode, error) {
+	n := &templateObject{}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, err := compileTemplateValue(obj[k])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		switch k {
+		case templateDataKey:
+			n.data = v
+		case templateWhenKey:
+			n.when = v
+		default:
+			n.keys = append(n.keys, k)
+			n.values = append(n.values, v)
+		}
+	}
+	return n, nil
+}
+
+// evalData evaluates value of $data property
+func (n *templateObject) evalData(s *templateScope) (interface{}, error) {
+	data, _, err := n.data.expand(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", templateDataKey, err)
+	}
+	return data, nil
+}
+
+// scope returns scope established by $data of the object
+func (n *templateObject) scope(s *templateScope) (*templateScope, error) {
+	data, err := n.evalData(s)
+	if err != nil {
+		return nil, err
+	}
+	return s.with(data), nil
+}
+
+// evalWhen evaluates value of $when property
+func (n *templateObject) evalWhen(s *templateScope) (bool, error) {
+	cond, _, err := n.when.expand(s)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", templateWhenKey, err)
+	}
+	if str, ok := cond.(string); ok && strings.Contains(str, "${") {
+		return false, nil // unresolved binding
+	}
+	return isTruthy(cond), nil
+}
+
+// expand expands object properties in already established scope
+func (n *templateObject) expand(s *templateScope) (interface{}, bool, error) {
+	if n.when != nil {
+		cond, err := n.evalWhen(s)
+		if err != nil {
+			return nil, false, err
+		}
+		if !cond {
+			return nil, false, nil
+		}
+	}
+	res := make(map[string]interface{}, len(n.keys))
+	for i, k := range n.keys {
+		v := n.values[i]
+		scope := s
+		if obj, ok := v.(*templateObject); ok && obj.data != nil {
+			var err error
+			if scope, err = obj.scope(s); err != nil {
+				return nil, false, fmt.Errorf("%s: %w", k, err)
+			}
+		}
+		expanded, keep, err := v.expand(scope)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", k, err)
+		}
+		if keep {
+			res[k] = expanded
+		}
+	}
+	return res, true, nil
+}
+
+// binding is a ${...} occurrence in a string
+type binding struct {
+	start int // index of "$"
+	end   int // index after closing "}"
+	expr  string
+}
+
+// findBindings finds ${...} occurrences honouring quoted strings and nested braces
+func findBindings(str string) ([]binding, error) {
+	var res []binding
+	for i := 0; i < len(str)-1; i++ {
+		if str[i] != '$' || str[i+1] != '{' {
+			continue
+		}
+		depth := 0
+		var quote byte
+		end := -1
+	scan:
+		for j := i + 2; j < len(str); j++ {
+			c := str[j]
+			switch {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '\'' || c == '"':
+				quote = c
+			case c == '{':
+				depth++
+			case c == '}':
+				if depth == 0 {
+					end = j
+					break scan
+				}
+				depth--
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed binding in %q", str)
+		}
+		res = append(res, binding{start: i, end: end + 1, expr: strings.TrimSpace(str[i+2 : end])})
+		i = end
+	}
+	return res, nil
+}
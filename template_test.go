@@ -0,0 +1,209 @@
+package cards
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var expenseData = map[string]interface{}{
+	"id":       42,
+	"title":    "Expense report",
+	"amount":   404.5,
+	"currency": "USD",
+	"urgent":   false,
+	"creator": map[string]interface{}{
+		"name": "Matt",
+	},
+	"items": []map[string]interface{}{
+		{"description": "Taxi", "price": 40.5},
+		{"description": "Hotel", "price": 364},
+	},
+	"facts": []map[string]interface{}{
+		{"key": "Department", "value": "Research"},
+		{"key": "Project", "value": "Cards"},
+	},
+}
+
+func TestExpandTemplate(t *testing.T) {
+	expected := mustReadFile("./test/template/expense_expanded.json")
+	c, err := ExpandTemplateWithHost(
+		[]byte(mustReadFile("./test/template/expense.json")),
+		expenseData,
+		map[string]string{"baseUrl": "https://example.com"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.StringIndent("", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+}
+
+func TestExpandCard(t *testing.T) {
+	tmpl := New([]Node{
+		&TextBlock{Text: "Hello, ${name}!"},
+		&TextBlock{Text: "${missing}"},
+	}, nil)
+	c, err := tmpl.Expand(map[string]string{"name": "Bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Body[0].(*TextBlock).Text; got != "Hello, Bob!" {
+		t.Errorf("expected %q, got %q", "Hello, Bob!", got)
+	}
+	if got := c.Body[1].(*TextBlock).Text; got != "${missing}" {
+		t.Errorf("expected unresolved binding to be kept, got %q", got)
+	}
+	if tmpl.Body[0].(*TextBlock).Type != "" || tmpl.Body[0].(*TextBlock).Text != "Hello, ${name}!" {
+		t.Errorf("expected template card not to be modified, got %+v", tmpl.Body[0])
+	}
+}
+
+func TestExpandTemplateExpressions(t *testing.T) {
+	tmpl := `{
+		"type": "AdaptiveCard",
+		"version": "1.3",
+		"body": [
+			{"type": "TextBlock", "$when": "${count(items) > 1}", "text": "${count(items)} items"},
+			{"type": "TextBlock", "$when": "${empty(items)}", "text": "No items"},
+			{"type": "TextBlock", "text": "Total: ${formatNumber(sum(items), 2)}", "maxLines": "${count(items)}"}
+		]
+	}`
+	c, err := ExpandTemplate([]byte(tmpl), map[string]interface{}{"items": []int{1000, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Body) != 2 {
+		t.Fatalf("expected 2 elements, got %d", len(c.Body))
+	}
+	if got := c.Body[0].(*TextBlock).Text; got != "2 items" {
+		t.Errorf("expected %q, got %q", "2 items", got)
+	}
+	total := c.Body[1].(*TextBlock)
+	if total.Text != "Total: 1,002.00" {
+		t.Errorf("expected %q, got %q", "Total: 1,002.00", total.Text)
+	}
+	if total.MaxLines != 2 {
+		t.Errorf("expected max lines 2, got %d", total.MaxLines)
+	}
+}
+
+func TestCompiledTemplateConcurrentExpand(t *testing.T) {
+	tmpl := MustCompileTemplate([]byte(mustReadFile("./test/template/expense.json")))
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, err := tmpl.Expand(map[string]interface{}{"title": fmt.Sprintf("Report %d", i)})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if got, expected := c.Body[0].(*TextBlock).Text, fmt.Sprintf("Report %d", i); got != expected {
+				errs <- fmt.Errorf("expected %q, got %q", expected, got)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestExpandJSON(t *testing.T) {
+	tmpl := MustCompileTemplate([]byte(`{"type":"AdaptiveCard","version":"1.3","body":[{"type":"TextBlock","text":"Hi ${name}"}]}`))
+	var buf bytes.Buffer
+	if err := tmpl.ExpandJSON(&buf, map[string]string{"name": "Bob"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"body":[{"text":"Hi Bob","type":"TextBlock"}],"type":"AdaptiveCard","version":"1.3"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, buf.String())
+	}
+}
+
+func TestExpandDecodesLikeParse(t *testing.T) {
+	tmpl := MustCompileTemplate([]byte(mustReadFile("./test/template/expense.json")))
+	var buf bytes.Buffer
+	if err := tmpl.ExpandJSON(&buf, expenseData); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsed.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+	expanded, err := tmpl.Expand(expenseData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expanded, parsed) {
+		t.Errorf("expected expanded card to be equal to parsed one:\n%+v\n%+v", expanded, parsed)
+	}
+}
+
+func TestCompileTemplateErrors(t *testing.T) {
+	for _, tmpl := range []string{
+		`{"type": "AdaptiveCard"`,
+		`{"type": "AdaptiveCard", "version": "${unclosed"}`,
+		`{"type": "AdaptiveCard", "version": "${1 +}"}`,
+	} {
+		if _, err := CompileTemplate([]byte(tmpl)); err == nil {
+			t.Errorf("%s: expected to have an error, got nil", tmpl)
+		}
+	}
+}
+
+func BenchmarkExpandTemplate(b *testing.B) {
+	template := []byte(mustReadFile("./test/template/expense.json"))
+	for i := 0; i < b.N; i++ {
+		if _, err := ExpandTemplate(template, expenseData); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiledTemplateExpand(b *testing.B) {
+	tmpl := MustCompileTemplate([]byte(mustReadFile("./test/template/expense.json")))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := tmpl.Expand(expenseData); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiledTemplateExpandJSON(b *testing.B) {
+	tmpl := MustCompileTemplate([]byte(mustReadFile("./test/template/expense.json")))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := tmpl.ExpandJSON(io.Discard, expenseData); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiledTemplateExpandParallel(b *testing.B) {
+	tmpl := MustCompileTemplate([]byte(mustReadFile("./test/template/expense.json")))
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := tmpl.Expand(expenseData); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
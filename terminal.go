@@ -0,0 +1,758 @@
+package cards
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultTerminalWidth is terminal width used when TerminalRenderer.Width is not set
+	DefaultTerminalWidth = 80
+	// MinTerminalWidth is the narrowest layout, smaller widths are rendered with it
+	MinTerminalWidth = 8
+)
+
+// TerminalRenderer renders cards as text with ANSI colours and box drawing, e.g. to debug bots over SSH.
+// Container styles are drawn as coloured boxes, columns are laid out side by side within the width
+// (stacked when they don't fit), Action.ShowCard cards are shown expanded below the actions.
+type TerminalRenderer struct {
+	Width   int  // terminal width in columns, DefaultTerminalWidth if not set, at least MinTerminalWidth
+	NoColor bool // disables ANSI escape sequences, box drawing is kept
+}
+
+// RenderTerminal writes the card to w with ANSI colours using DefaultTerminalWidth
+func RenderTerminal(w io.Writer, c *Card) error {
+	r := &TerminalRenderer{}
+	return r.Render(w, c)
+}
+
+// Render writes the card to w
+func (r *TerminalRenderer) Render(w io.Writer, c *Card) error {
+	if err := c.Prepare(); err != nil {
+		return err
+	}
+	width := r.Width
+	if width <= 0 {
+		width = DefaultTerminalWidth
+	}
+	width = maxInt(width, MinTerminalWidth)
+	t := &termWriter{color: !r.NoColor}
+	var b strings.Builder
+	for _, l := range t.box(t.body(c.Body, c.Actions, width-4), width, "", "") {
+		b.WriteString(l.s + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// termLine is a rendered line and its visible width
+type termLine struct {
+	s string
+	w int
+}
+
+// termSpan is a piece of text with SGR parameters of its style, e.g. "1;34"
+type termSpan struct {
+	text string
+	sgr  string
+}
+
+// termWriter lays out elements as lines of given width
+type termWriter struct {
+	color bool
+}
+
+// styled wraps text with ANSI escape sequences, the text must not contain control characters
+func (t *termWriter) styled(text, sgr string) string {
+	if !t.color || sgr == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + sgr + "m" + text + "\x1b[0m"
+}
+
+func (t *termWriter) line(spans []termSpan) termLine {
+	var l termLine
+	for _, sp := range spans {
+		text := termText(sp.text, false)
+		l.s += t.styled(text, sp.sgr)
+		l.w += displayWidth(text)
+	}
+	return l
+}
+
+func (t *termWriter) plain(text, sgr string) termLine {
+	return t.line([]termSpan{{text: text, sgr: sgr}})
+}
+
+// padLine adds spaces to the line up to the width
+func padLine(l termLine, width int) string {
+	if l.w >= width {
+		return l.s
+	}
+	return l.s + strings.Repeat(" ", width-l.w)
+}
+
+// body renders card or container items followed by actions
+func (t *termWriter) body(items []Node, actions []Node, width int) []termLine {
+	lines := t.items(items, width)
+	if a := t.actions(actions, width); len(a) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, termLine{})
+		}
+		lines = append(lines, a...)
+	}
+	return lines
+}
+
+// items renders elements stacked vertically
+func (t *termWriter) items(nodes []Node, width int) []termLine {
+	var lines []termLine
+	for _, n := range nodes {
+		if isNilNode(n) {
+			continue
+		}
+		if visible, _ := fieldValue(n, "IsVisible").(*bool); visible != nil && !*visible {
+			continue
+		}
+		el := t.element(n, width)
+		if len(el) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			spacing, _ := fieldValue(n, "Spacing").(Spacing)
+			if sep, _ := fieldValue(n, "Separator").(*bool); sep != nil && *sep {
+				lines = append(lines, t.plain(strings.Repeat("─", maxInt(0, width)), "2"))
+			} else if termSpacing(spacing) {
+				lines = append(lines, termLine{})
+			}
+		}
+		lines = append(lines, el...)
+	}
+	return lines
+}
+
+// termSpacing tells if the spacing is large enough to be an empty line
+func termSpacing(s Spacing) bool {
+	for _, large := range []Spacing{SpacingMedium, SpacingLarge, SpacingExtraLarge, SpacingPadding} {
+		if strings.EqualFold(string(s), string(large)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *termWriter) element(n Node, width int) []termLine {
+	switch n := n.(type) {
+	case *TextBlock:
+		sgr := textSGR(n.Size, n.Weight, n.Color, isTrue(n.IsSubtle))
+		lines := t.wrap([]termSpan{{text: markdownText(n.Text), sgr: sgr}}, width, isTrue(n.Wrap), int(n.MaxLines))
+		return alignLines(lines, width, n.HorizontalAlignment)
+	case *RichTextBlock:
+		var spans []termSpan
+		for _, run := range n.Inlines {
+			if run != nil {
+				spans = append(spans, termSpan{text: run.Text, sgr: runSGR(run)})
+			}
+		}
+		return alignLines(t.wrap(spans, width, true, 0), width, n.HorizontalAlignment)
+	case *Image:
+		return t.wrap([]termSpan{{text: mediaLabel("Image", n.AltText, n.URL), sgr: "2"}}, width, false, 0)
+	case *Media:
+		url := n.Poster
+		if len(n.Sources) > 0 {
+			url = n.Sources[0].URL
+		}
+		return t.wrap([]termSpan{{text: mediaLabel("Media", n.AltText, url), sgr: "2"}}, width, false, 0)
+	case *Container:
+		return t.styledBox(n.Items, n.Style, width)
+	case *ColumnSet:
+		return t.columnSet(n, width)
+	case *FactSet:
+		return t.factSet(n, width)
+	case *ImageSet:
+		var lines []termLine
+		for _, img := range n.Images {
+			if img != nil {
+				lines = append(lines, t.element(img, width)...)
+			}
+		}
+		return lines
+	case *ActionSet:
+		return t.actions(n.Actions, width)
+	case *InputText, *InputNumber, *InputDate, *InputTime, *InputToggle, *InputChoiceSet:
+		tw := &textWriter{}
+		return t.wrap([]termSpan{{text: tw.input(n)}}, width, true, 0)
+	}
+	// unknown element is replaced with its fallback
+	if f := nodeFallback(n); f != nil && f.Element != nil {
+		return t.element(f.Element, width)
+	}
+	return nil
+}
+
+func mediaLabel(kind, alt, url string) string {
+	if alt != "" {
+		kind += ": " + alt
+	}
+	return "[" + kind + "] " + url
+}
+
+// styledBox renders items in a coloured box if the container has a style
+func (t *termWriter) styledBox(items []Node, style ContainerStyle, width int) []termLine {
+	if style == "" || strings.EqualFold(string(style), string(ContainerStyleDefault)) || width < 5 {
+		return t.items(items, width)
+	}
+	return t.box(t.items(items, width-4), width, styleSGR(style), "")
+}
+
+// box draws border around the lines, title is written in the top border
+func (t *termWriter) box(lines []termLine, width int, sgr, title string) []termLine {
+	inner := width - 4
+	top := "─"
+	if title != "" {
+		top += " " + truncateWidth(termText(title, false), inner-2) + " "
+	}
+	top += strings.Repeat("─", maxInt(0, width-2-displayWidth(top)))
+	res := []termLine{t.plain("┌"+top+"┐", sgr)}
+	for _, l := range lines {
+		res = append(res, termLine{
+			s: t.styled("│", sgr) + " " + padLine(l, inner) + " " + t.styled("│", sgr),
+			w: width,
+		})
+	}
+	return append(res, t.plain("└"+strings.Repeat("─", maxInt(0, width-2))+"┘", sgr))
+}
+
+func (t *termWriter) columnSet(n *ColumnSet, width int) []termLine {
+	var columns []*Column
+	for _, c := range n.Columns {
+		if c != nil && (c.IsVisible == nil || *c.IsVisible) {
+			columns = append(columns, c)
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	gaps := make([]string, len(columns))
+	avail := width
+	for i, c := range columns[1:] {
+		gaps[i+1] = " "
+		if isTrue(c.Separator) {
+			gaps[i+1] = " " + t.styled("│", "2") + " "
+			avail -= 2
+		}
+		avail--
+	}
+	if avail < len(columns) {
+		// no room side by side, columns are stacked like container items
+		var lines []termLine
+		for _, c := range columns {
+			lines = append(lines, t.styledBox(c.Items, c.Style, width)...)
+		}
+		return lines
+	}
+	widths := t.columnWidths(columns, avail)
+	var cells [][]termLine
+	height := 0
+	for i, c := range columns {
+		lines := t.styledBox(c.Items, c.Style, widths[i])
+		cells = append(cells, lines)
+		height = maxInt(height, len(lines))
+	}
+	lines := make([]termLine, 0, height)
+	for row := 0; row < height; row++ {
+		var b strings.Builder
+		for i := range columns {
+			b.WriteString(gaps[i])
+			var cell termLine
+			if row < len(cells[i]) {
+				cell = cells[i][row]
+			}
+			b.WriteString(padLine(cell, widths[i]))
+		}
+		lines = append(lines, termLine{s: b.String(), w: sumInts(widths) + width - avail})
+	}
+	return lines
+}
+
+// columnWidths splits width between columns: pixel widths are converted to columns (8px per column),
+// auto columns take width of their content and the rest is shared by weights
+func (t *termWriter) columnWidths(columns []*Column, width int) []int {
+	widths := make([]int, len(columns))
+	weights := make([]float64, len(columns))
+	total := 0.0
+	rest := width
+	for i, c := range columns {
+		w := c.Width
+		if w == "" {
+			w = ColumnWidthStretch
+		}
+		switch px, err := ParsePixels(string(w)); {
+		case err == nil:
+			widths[i] = maxInt(1, px/8)
+		case strings.EqualFold(string(w), string(ColumnWidthAuto)):
+			widths[i] = t.autoWidth(c, width)
+		case strings.EqualFold(string(w), string(ColumnWidthStretch)):
+			weights[i] = 1
+		default:
+			weights[i], _ = strconv.ParseFloat(string(w), 64)
+		}
+		rest -= widths[i]
+		total += weights[i]
+	}
+	if total > 0 {
+		// auto columns leave place for weighted ones
+		share := width / len(columns)
+		for i, c := range columns {
+			if strings.EqualFold(string(c.Width), string(ColumnWidthAuto)) && widths[i] > share {
+				rest += widths[i] - share
+				widths[i] = share
+			}
+		}
+		left := maxInt(0, rest)
+		last := -1
+		for i := range columns {
+			if weights[i] > 0 {
+				widths[i] = int(float64(left) * weights[i] / total)
+				rest -= widths[i]
+				last = i
+			}
+		}
+		if rest > 0 {
+			widths[last] += rest
+		}
+	}
+	for i := range widths {
+		widths[i] = maxInt(1, widths[i])
+	}
+	// shrink the widest columns if content doesn't fit
+	for sumInts(widths) > width {
+		i := 0
+		for j := range widths {
+			if widths[j] > widths[i] {
+				i = j
+			}
+		}
+		if widths[i] <= 1 {
+			break
+		}
+		widths[i]--
+	}
+	return widths
+}
+
+// autoWidth returns width of the column content including border of styled column
+func (t *termWriter) autoWidth(c *Column, width int) int {
+	border := 0
+	if c.Style != "" && !strings.EqualFold(string(c.Style), string(ContainerStyleDefault)) && width >= 5 {
+		border = 4
+	}
+	w := 0
+	for _, l := range t.items(c.Items, width-border) {
+		w = maxInt(w, l.w)
+	}
+	return w + border
+}
+
+func (t *termWriter) factSet(n *FactSet, width int) []termLine {
+	titleWidth := 0
+	for _, f := range n.Facts {
+		if f != nil {
+			titleWidth = maxInt(titleWidth, displayWidth(termText(strings.TrimSpace(f.Title), false)))
+		}
+	}
+	titleWidth = minInt(titleWidth, width/2)
+	valueWidth := width - titleWidth - 1
+	var lines []termLine
+	for _, f := range n.Facts {
+		if f == nil {
+			continue
+		}
+		title := t.wrap([]termSpan{{text: strings.TrimSpace(f.Title), sgr: "1"}}, titleWidth, true, 0)
+		value := t.wrap([]termSpan{{text: markdownText(f.Value)}}, valueWidth, true, 0)
+		for i := 0; i < maxInt(len(title), len(value)); i++ {
+			var l, v termLine
+			if i < len(title) {
+				l = title[i]
+			}
+			if i < len(value) {
+				v = value[i]
+			}
+			lines = append(lines, termLine{s: padLine(l, titleWidth) + " " + v.s, w: titleWidth + 1 + v.w})
+		}
+	}
+	return lines
+}
+
+// actions renders buttons wrapped to the width followed by expanded Action.ShowCard cards
+func (t *termWriter) actions(actions []Node, width int) []termLine {
+	var lines, cards []termLine
+	var cur termLine
+	for _, a := range actions {
+		if isNilNode(a) {
+			continue
+		}
+		a = termAction(a)
+		if a == nil {
+			continue
+		}
+		title := firstNonEmpty(fieldString(a, "Title"), a.NodeType())
+		button := t.plain(truncateWidth(termText("[ "+title+" ]", false), width), actionSGR(a))
+		if cur.w > 0 && cur.w+1+button.w > width {
+			lines = append(lines, cur)
+			cur = termLine{}
+		}
+		if cur.w > 0 {
+			cur.s += " "
+			cur.w++
+		}
+		cur.s += button.s
+		cur.w += button.w
+		if sc, ok := a.(*ActionShowCard); ok && width >= 5 {
+			cards = append(cards, t.box(t.body(sc.Card.Body, sc.Card.Actions, width-4), width, "2", title)...)
+		}
+	}
+	if cur.w > 0 {
+		lines = append(lines, cur)
+	}
+	return append(lines, cards...)
+}
+
+// termAction returns the action or its fallback if the action type is unknown
+func termAction(a Node) Node {
+	switch a.(type) {
+	case *ActionOpenURL, *ActionShowCard, *ActionSubmit, *ActionToggleVisibility:
+		return a
+	}
+	if f := nodeFallback(a); f != nil && f.Element != nil {
+		return termAction(f.Element)
+	}
+	return nil
+}
+
+// wrap lays out text spans in lines of the width. If wrap is false, lines are cut at the width.
+// Lines beyond maxLines are dropped, 0 means no limit.
+func (t *termWriter) wrap(spans []termSpan, width int, wrap bool, maxLines int) []termLine {
+	width = maxInt(1, width)
+	var lines [][]termSpan
+	var cur []termSpan
+	curWidth := 0
+	wrapped := false
+	add := func(text, sgr string) {
+		if n := len(cur); n > 0 && cur[n-1].sgr == sgr {
+			cur[n-1].text += text
+		} else {
+			cur = append(cur, termSpan{text: text, sgr: sgr})
+		}
+		curWidth += displayWidth(text)
+	}
+	flush := func(byWidth bool) {
+		if n := len(cur); n > 0 {
+			cur[n-1].text = strings.TrimRightFunc(cur[n-1].text, unicode.IsSpace)
+		}
+		lines = append(lines, cur)
+		cur, curWidth, wrapped = nil, 0, byWidth
+	}
+	for _, sp := range spans {
+		for i, para := range strings.Split(termText(sp.text, true), "\n") {
+			if i > 0 {
+				flush(false)
+			}
+			for _, word := range splitWords(para) {
+				w := displayWidth(word)
+				if strings.TrimSpace(word) == "" {
+					if !(wrapped && curWidth == 0) {
+						add(word, sp.sgr)
+					}
+					continue
+				}
+				if !wrap {
+					add(word, sp.sgr)
+					continue
+				}
+				if curWidth > 0 && curWidth+w > width {
+					flush(true)
+				}
+				// words longer than the line are broken
+				for displayWidth(word) > width {
+					var head string
+					head, word = cutWidth(word, width)
+					add(head, sp.sgr)
+					flush(true)
+				}
+				add(word, sp.sgr)
+			}
+		}
+	}
+	flush(false)
+
+	truncated := false
+	if maxLines > 0 && len(lines) > maxLines {
+		lines = lines[:maxLines]
+		truncated = true
+	}
+	res := make([]termLine, 0, len(lines))
+	for i, l := range lines {
+		cut := truncated && i == len(lines)-1
+		res = append(res, t.line(truncateSpans(l, width, cut)))
+	}
+	return res
+}
+
+// splitWords splits text into words and runs of spaces
+func splitWords(s string) []string {
+	var words []string
+	start, space := 0, false
+	for i, r := range s {
+		if i > start && unicode.IsSpace(r) != space {
+			words = append(words, s[start:i])
+			start = i
+		}
+		if i == start {
+			space = unicode.IsSpace(r)
+		}
+	}
+	if start < len(s) {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+// truncateSpans cuts spans to the width, ellipsis is added if text is cut or more is true
+func truncateSpans(spans []termSpan, width int, more bool) []termSpan {
+	total := 0
+	for _, sp := range spans {
+		total += displayWidth(sp.text)
+	}
+	if total <= width && !more {
+		return spans
+	}
+	left := width - 1
+	if total < width {
+		left = total
+	}
+	var res []termSpan
+	for _, sp := range spans {
+		text, _ := cutWidth(sp.text, maxInt(0, left))
+		left -= displayWidth(text)
+		res = append(res, termSpan{text: text, sgr: sp.sgr})
+	}
+	if n := len(res); n > 0 {
+		res[n-1].text += "…"
+	}
+	return res
+}
+
+// alignLines pads lines for center and right alignment
+func alignLines(lines []termLine, width int, a HorizontalAlignment) []termLine {
+	for i, l := range lines {
+		space := width - l.w
+		switch {
+		case space <= 0:
+			continue
+		case strings.EqualFold(string(a), string(HorizontalAlignmentCenter)):
+			space /= 2
+		case !strings.EqualFold(string(a), string(HorizontalAlignmentRight)):
+			continue
+		}
+		lines[i] = termLine{s: strings.Repeat(" ", space) + l.s, w: l.w + space}
+	}
+	return lines
+}
+
+// textSGR maps text properties to SGR parameters
+func textSGR(size TextSize, weight FontWeight, color Color, subtle bool) string {
+	var params []string
+	switch {
+	case strings.EqualFold(string(size), string(SizeExtraLarge)):
+		params = append(params, "1", "4")
+	case strings.EqualFold(string(size), string(SizeLarge)), strings.EqualFold(string(weight), string(WeightBolder)):
+		params = append(params, "1")
+	case subtle, strings.EqualFold(string(weight), string(WeightLighter)), strings.EqualFold(string(size), string(SizeSmall)):
+		params = append(params, "2")
+	}
+	if c := colorSGR(color); c != "" {
+		params = append(params, c)
+	}
+	return strings.Join(params, ";")
+}
+
+func runSGR(run *TextRun) string {
+	params := []string{}
+	if sgr := textSGR(run.Size, run.Weight, run.Color, isTrue(run.IsSubtle)); sgr != "" {
+		params = append(params, sgr)
+	}
+	for _, p := range []struct {
+		set *bool
+		sgr string
+	}{
+		{run.Italic, "3"},
+		{run.Underline, "4"},
+		{run.Highlight, "7"},
+		{run.Strikethrough, "9"},
+	} {
+		if isTrue(p.set) {
+			params = append(params, p.sgr)
+		}
+	}
+	return strings.Join(params, ";")
+}
+
+func colorSGR(c Color) string {
+	switch strings.ToLower(string(c)) {
+	case strings.ToLower(string(ColorDark)):
+		return "90"
+	case strings.ToLower(string(ColorLight)):
+		return "97"
+	case strings.ToLower(string(ColorAccent)):
+		return "34"
+	case strings.ToLower(string(ColorGood)):
+		return "32"
+	case strings.ToLower(string(ColorWarning)):
+		return "33"
+	case strings.ToLower(string(ColorAttention)):
+		return "31"
+	}
+	return ""
+}
+
+func styleSGR(s ContainerStyle) string {
+	switch strings.ToLower(string(s)) {
+	case strings.ToLower(string(ContainerStyleEmphasis)):
+		return "90"
+	case strings.ToLower(string(ContainerStyleAccent)):
+		return "34"
+	case strings.ToLower(string(ContainerStyleGood)):
+		return "32"
+	case strings.ToLower(string(ContainerStyleWarning)):
+		return "33"
+	case strings.ToLower(string(ContainerStyleAttention)):
+		return "31"
+	}
+	return ""
+}
+
+func actionSGR(a Node) string {
+	style, _ := fieldValue(a, "Style").(ActionStyle)
+	switch strings.ToLower(string(style)) {
+	case strings.ToLower(string(ActionStylePositive)):
+		return "1;32"
+	case strings.ToLower(string(ActionStyleDestructive)):
+		return "1;31"
+	}
+	return "1"
+}
+
+// truncateWidth cuts the text at word boundary so it fits width columns including ellipsis
+func truncateWidth(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return ""
+	}
+	cut, _ := cutWidth(s, width-1)
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) }) + "…"
+}
+
+// cutWidth splits the text after the characters fitting width columns. At least one character
+// goes to head if width is positive so long words are always broken.
+func cutWidth(s string, width int) (head, tail string) {
+	w := 0
+	for i, r := range s {
+		rw := runeWidth(r)
+		if w+rw > width && (i > 0 || width <= 0) {
+			return s[:i], s[i:]
+		}
+		w += rw
+	}
+	return s, ""
+}
+
+// termText removes control characters which could change the terminal state, e.g. escape sequences
+// from card text. Tabs become spaces, new lines are kept if keepNewLines is true.
+func termText(s string, keepNewLines bool) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t':
+			return ' '
+		case r == '\n' && keepNewLines:
+			return r
+		case r < 0x20, r >= 0x7f && r <= 0x9f:
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// displayWidth returns number of terminal columns the text takes
+func displayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		w += runeWidth(r)
+	}
+	return w
+}
+
+// wideRunes are ranges of East Asian wide and fullwidth characters and emoji taking two columns
+var wideRunes = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0},
+	{0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267F, 0x267F},
+	{0x2693, 0x2693}, {0x26A1, 0x26A1}, {0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5},
+	{0x26CE, 0x26CE}, {0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B}, {0x2728, 0x2728},
+	{0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19}, {0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x18CFF}, {0x1B000, 0x1B2FF}, {0x1F004, 0x1F004},
+	{0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F251}, {0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF}, {0x1F7E0, 0x1F7EB}, {0x1F90C, 0x1F9FF}, {0x1FA70, 0x1FAFF}, {0x20000, 0x3FFFD},
+}
+
+// runeWidth returns number of terminal columns the character takes:
+// 0 for combining marks and zero width characters, 2 for wide characters and emoji
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || r >= 0xFE00 && r <= 0xFE0F || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case r < 0x1100:
+		return 1
+	}
+	for _, rng := range wideRunes {
+		if r < rng[0] {
+			break
+		}
+		if r <= rng[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func sumInts(values []int) int {
+	s := 0
+	for _, v := range values {
+		s += v
+	}
+	return s
+}
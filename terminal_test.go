@@ -0,0 +1,205 @@
+package cards
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTerminal(t *testing.T) {
+	for _, name := range []string{
+		"example",
+		"inputs",
+		"media",
+		"rich",
+	} {
+		c, err := Parse(strings.NewReader(mustReadFile("./test/" + name + ".json")))
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		r := &TerminalRenderer{Width: 60, NoColor: true}
+		var b strings.Builder
+		if err := r.Render(&b, c); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		expected := mustReadFile("./test/terminal/" + name + ".txt")
+		if got := b.String(); got != expected {
+			t.Errorf("%s: expected:\n%s\nbut got:\n%s", name, expected, got)
+		}
+	}
+}
+
+func TestRenderTerminalColors(t *testing.T) {
+	c := New([]Node{
+		&Container{Style: ContainerStyleGood, Items: []Node{
+			&TextBlock{Text: "Build passed", Weight: WeightBolder, Color: ColorGood},
+		}},
+		&ColumnSet{Columns: []*Column{
+			{Width: ColumnWidthAuto, Items: []Node{&TextBlock{Text: "Tests", IsSubtle: TruePtr()}}},
+			{Width: ColumnWidthStretch, Separator: TruePtr(), Items: []Node{&TextBlock{Text: "120"}}},
+		}},
+	}, []Node{
+		&ActionSubmit{Title: "Rerun", Style: ActionStyleDestructive},
+	})
+	r := &TerminalRenderer{Width: 24}
+	var b strings.Builder
+	if err := r.Render(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	expected := "┌──────────────────────┐\n" +
+		"│ \x1b[32m┌──────────────────┐\x1b[0m │\n" +
+		"│ \x1b[32m│\x1b[0m \x1b[1;32mBuild passed\x1b[0m     \x1b[32m│\x1b[0m │\n" +
+		"│ \x1b[32m└──────────────────┘\x1b[0m │\n" +
+		"│ \x1b[2mTests\x1b[0m \x1b[2m│\x1b[0m 120          │\n" +
+		"│                      │\n" +
+		"│ \x1b[1;31m[ Rerun ]\x1b[0m            │\n" +
+		"└──────────────────────┘\n"
+	if got := b.String(); got != expected {
+		t.Errorf("expected:\n%q\nbut got:\n%q", expected, got)
+	}
+}
+
+func TestTerminalWrap(t *testing.T) {
+	tw := &termWriter{}
+	for _, tc := range []struct {
+		text     string
+		wrap     bool
+		maxLines int
+		expected []string
+	}{
+		{"one two three four five", true, 0, []string{"one two", "three four", "five"}},
+		{"one two three four five", true, 2, []string{"one two", "three fou…"}},
+		{"one two three four", false, 0, []string{"one two t…"}},
+		{"abcdefghijklmnopqrstuvwxyz", true, 0, []string{"abcdefghij", "klmnopqrst", "uvwxyz"}},
+		{"first\nsecond line", true, 0, []string{"first", "second", "line"}},
+	} {
+		var got []string
+		for _, l := range tw.wrap([]termSpan{{text: tc.text}}, 10, tc.wrap, tc.maxLines) {
+			got = append(got, l.s)
+		}
+		if strings.Join(got, "|") != strings.Join(tc.expected, "|") {
+			t.Errorf("%q: expected %q but got %q", tc.text, tc.expected, got)
+		}
+	}
+}
+
+func TestRenderTerminalNarrow(t *testing.T) {
+	c := New([]Node{
+		&Container{Style: ContainerStyleGood, Items: []Node{
+			&Container{Style: ContainerStyleAttention, Items: []Node{&TextBlock{Text: "deeply nested", Wrap: TruePtr()}}},
+		}},
+		&FactSet{Facts: []*Fact{{Title: "Key", Value: "Value"}}},
+	}, []Node{&ActionShowCard{Title: "More", Card: NestedCard{Body: []Node{&TextBlock{Text: "more"}}}}})
+	for _, width := range []int{1, 2, 5, MinTerminalWidth} {
+		var b strings.Builder
+		if err := (&TerminalRenderer{Width: width, NoColor: true}).Render(&b, c); err != nil {
+			t.Fatal(err)
+		}
+		for _, l := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+			if w := displayWidth(l); w != MinTerminalWidth {
+				t.Errorf("width %d: expected line %q to be %d columns but got %d", width, l, MinTerminalWidth, w)
+			}
+		}
+	}
+}
+
+func TestRenderTerminalControlCharacters(t *testing.T) {
+	c := New([]Node{
+		&TextBlock{Text: "evil\x1b]0;title\x07\x1b[2Jtext\u009b31m"},
+		&FactSet{Facts: []*Fact{{Title: "\x1b[31mKey", Value: "a\tb"}}},
+	}, []Node{&ActionSubmit{Title: "Go\x1b[5m"}})
+	var b strings.Builder
+	if err := (&TerminalRenderer{Width: 40}).Render(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	// only colour sequences of the renderer are left
+	rest := got
+	for _, sgr := range []string{"\x1b[0m", "\x1b[1m", "\x1b[1;32m", "\x1b[2m"} {
+		rest = strings.ReplaceAll(rest, sgr, "")
+	}
+	if strings.ContainsAny(rest, "\x1b\x07\t\u009b") {
+		t.Errorf("expected control characters to be removed, got %q", got)
+	}
+	if !strings.Contains(got, "evil]0;title[2Jtext31m") || !strings.Contains(got, "a b") {
+		t.Errorf("expected text to be kept, got %q", got)
+	}
+}
+
+func TestRenderTerminalWideCharacters(t *testing.T) {
+	c := New([]Node{
+		&Container{Style: ContainerStyleEmphasis, Items: []Node{
+			&TextBlock{Text: "日本語のテキストはとても長いので折り返されます", Wrap: TruePtr()},
+			&TextBlock{Text: "🎉 Released 🚀 é"},
+		}},
+		&ColumnSet{Columns: []*Column{
+			{Width: ColumnWidthAuto, Items: []Node{&TextBlock{Text: "名前"}}},
+			{Items: []Node{&TextBlock{Text: "値 ✅"}}},
+		}},
+	}, []Node{&ActionSubmit{Title: "送信"}})
+	var b strings.Builder
+	if err := (&TerminalRenderer{Width: 30, NoColor: true}).Render(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		if w := displayWidth(l); w != 30 {
+			t.Errorf("expected line %q to be 30 columns but got %d", l, w)
+		}
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	for s, expected := range map[string]int{
+		"abc": 3,
+		"日本":  4,
+		"🎉!":  3,
+		"é":  1,
+		"👍️":  2,
+	} {
+		if got := displayWidth(s); got != expected {
+			t.Errorf("expected %q to take %d columns but got %d", s, expected, got)
+		}
+	}
+	if head, tail := cutWidth("日本語", 3); head != "日" || tail != "本語" {
+		t.Errorf("unexpected cut %q %q", head, tail)
+	}
+}
+
+func TestRenderTerminalFitsWidth(t *testing.T) {
+	cards := []*Card{New([]Node{
+		&ColumnSet{Columns: []*Column{
+			{Width: ColumnWidthAuto, Items: []Node{&TextBlock{Text: "Tests"}}},
+			{Separator: TruePtr(), Style: ContainerStyleGood, Items: []Node{&TextBlock{Text: "120 passed", Wrap: TruePtr()}}},
+			{Width: "80px", Separator: TruePtr(), Items: []Node{&TextBlock{Text: "x"}}},
+		}},
+		&Container{Style: ContainerStyleGood, Items: []Node{
+			&Container{Style: ContainerStyleAttention, Items: []Node{
+				&ColumnSet{Columns: []*Column{
+					{Items: []Node{&TextBlock{Text: "a"}}},
+					{Items: []Node{&TextBlock{Text: "b"}}},
+					{Items: []Node{&TextBlock{Text: "c"}}},
+					{Items: []Node{&TextBlock{Text: "d"}}},
+				}},
+			}},
+		}},
+	}, nil)}
+	for _, name := range []string{"example", "inputs", "media", "rich"} {
+		c, err := Parse(strings.NewReader(mustReadFile("./test/" + name + ".json")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cards = append(cards, c)
+	}
+	for i, c := range cards {
+		for width := MinTerminalWidth; width <= 40; width++ {
+			var b strings.Builder
+			if err := (&TerminalRenderer{Width: width, NoColor: true}).Render(&b, c); err != nil {
+				t.Fatal(err)
+			}
+			for _, l := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+				if w := displayWidth(l); w != width {
+					t.Errorf("card %d, width %d: expected line %q to be %d columns but got %d", i, width, l, width, w)
+				}
+			}
+		}
+	}
+}
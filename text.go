@@ -0,0 +1,331 @@
+package cards
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// RenderText renders the card as plain text for channels without card support (SMS, email text parts, logs).
+// Elements are written in reading order, large text becomes headings, images and URLs become links,
+// inputs are shown as labelled placeholders and actions as numbered lists.
+// Hidden elements are skipped.
+func RenderText(c *Card) (string, error) {
+	return renderText(c, false)
+}
+
+// RenderMarkdown renders the card as markdown, see RenderText
+func RenderMarkdown(c *Card) (string, error) {
+	return renderText(c, true)
+}
+
+func renderText(c *Card, markdown bool) (string, error) {
+	if err := c.Prepare(); err != nil {
+		return "", err
+	}
+	t := &textWriter{markdown: markdown}
+	return strings.Join(t.body(c.Body, c.Actions), "\n\n"), nil
+}
+
+// textWriter renders elements as blocks of text separated with blank lines
+type textWriter struct {
+	markdown bool
+}
+
+// body renders card or container items followed by actions
+func (t *textWriter) body(items []Node, actions []Node) []string {
+	return append(t.items(items), t.actions(actions)...)
+}
+
+func (t *textWriter) items(nodes []Node) []string {
+	var blocks []string
+	for _, n := range nodes {
+		blocks = append(blocks, t.element(n)...)
+	}
+	return blocks
+}
+
+func (t *textWriter) element(n Node) []string {
+	if isNilNode(n) {
+		return nil
+	}
+	if visible, _ := fieldValue(n, "IsVisible").(*bool); visible != nil && !*visible {
+		return nil
+	}
+	switch n := n.(type) {
+	case *TextBlock:
+		return t.textBlock(n)
+	case *RichTextBlock:
+		return t.richTextBlock(n)
+	case *Image:
+		return []string{t.link(n.AltText, n.URL, "Image")}
+	case *Media:
+		if len(n.Sources) == 0 {
+			return nil
+		}
+		return []string{t.link(n.AltText, n.Sources[0].URL, "Media")}
+	case *Container:
+		return t.items(n.Items)
+	case *ColumnSet:
+		var blocks []string
+		for _, c := range n.Columns {
+			if c != nil && (c.IsVisible == nil || *c.IsVisible) {
+				blocks = append(blocks, t.items(c.Items)...)
+			}
+		}
+		return blocks
+	case *FactSet:
+		return t.factSet(n)
+	case *ImageSet:
+		lines := make([]string, 0, len(n.Images))
+		for _, img := range n.Images {
+			if img != nil {
+				lines = append(lines, "- "+t.link(img.AltText, img.URL, "Image"))
+			}
+		}
+		return []string{strings.Join(lines, "\n")}
+	case *ActionSet:
+		return t.actions(n.Actions)
+	case *InputText, *InputNumber, *InputDate, *InputTime, *InputToggle, *InputChoiceSet:
+		return []string{t.input(n)}
+	}
+	// unknown element is replaced with its fallback
+	if f := nodeFallback(n); f != nil && f.Element != nil {
+		return t.element(f.Element)
+	}
+	return nil
+}
+
+func (t *textWriter) textBlock(n *TextBlock) []string {
+	text := strings.TrimSpace(n.Text)
+	if text == "" {
+		return nil
+	}
+	if level := headingLevel(n.Size, n.Weight); level > 0 {
+		return []string{t.heading(level, strings.Join(strings.Fields(text), " "))}
+	}
+	if t.markdown {
+		return []string{text}
+	}
+	return []string{markdownText(text)}
+}
+
+// headingLevel returns heading level of the text, 0 means it isn't a heading
+func headingLevel(size TextSize, weight FontWeight) int {
+	switch {
+	case strings.EqualFold(string(size), string(SizeExtraLarge)):
+		return 1
+	case strings.EqualFold(string(size), string(SizeLarge)):
+		return 2
+	case strings.EqualFold(string(size), string(SizeMedium)) && strings.EqualFold(string(weight), string(WeightBolder)):
+		return 3
+	}
+	return 0
+}
+
+// heading renders markdown heading or underlined text
+func (t *textWriter) heading(level int, text string) string {
+	if t.markdown {
+		return strings.Repeat("#", level) + " " + text
+	}
+	text = markdownInlineText(text)
+	underline := "-"
+	if level == 1 {
+		underline = "="
+	}
+	return text + "\n" + strings.Repeat(underline, utf8.RuneCountInString(text))
+}
+
+func (t *textWriter) richTextBlock(n *RichTextBlock) []string {
+	var b strings.Builder
+	for _, run := range n.Inlines {
+		if run == nil {
+			continue
+		}
+		if !t.markdown {
+			b.WriteString(run.Text)
+			continue
+		}
+		s := escapeMarkdown(run.Text)
+		if strings.EqualFold(string(run.Weight), string(WeightBolder)) {
+			s = wrapText(s, "**")
+		}
+		if isTrue(run.Italic) {
+			s = wrapText(s, "_")
+		}
+		if isTrue(run.Strikethrough) {
+			s = wrapText(s, "~~")
+		}
+		if a, ok := run.SelectAction.(*ActionOpenURL); ok {
+			s = "[" + s + "](" + a.URL + ")"
+		}
+		b.WriteString(s)
+	}
+	if text := strings.TrimSpace(b.String()); text != "" {
+		return []string{text}
+	}
+	return nil
+}
+
+// wrapText wraps text with markdown emphasis keeping surrounding spaces outside
+func wrapText(s, mark string) string {
+	text := strings.TrimSpace(s)
+	if text == "" {
+		return s
+	}
+	i := strings.Index(s, text)
+	return s[:i] + mark + text + mark + s[i+len(text):]
+}
+
+// link renders link as "[text](url)" in markdown and as "text (url)" in plain text
+func (t *textWriter) link(text, url, defaultText string) string {
+	switch {
+	case t.markdown && text == "":
+		return "[" + defaultText + "](" + url + ")"
+	case t.markdown:
+		return "[" + escapeMarkdown(text) + "](" + url + ")"
+	case text == "":
+		return url
+	}
+	return text + " (" + url + ")"
+}
+
+func (t *textWriter) factSet(n *FactSet) []string {
+	var lines []string
+	if t.markdown {
+		lines = append(lines, "| | |", "| --- | --- |")
+	}
+	for _, f := range n.Facts {
+		if f == nil {
+			continue
+		}
+		title := strings.TrimSuffix(strings.TrimSpace(f.Title), ":")
+		if t.markdown {
+			cell := strings.NewReplacer("|", `\|`, "\n", " ")
+			lines = append(lines, "| **"+cell.Replace(title)+"** | "+cell.Replace(f.Value)+" |")
+		} else {
+			lines = append(lines, title+": "+markdownInlineText(f.Value))
+		}
+	}
+	return []string{strings.Join(lines, "\n")}
+}
+
+// input renders input as its label followed by placeholder, e.g. "Name (required): [Enter name]"
+func (t *textWriter) input(n Node) string {
+	label := fieldString(n, "Label")
+	if r, _ := fieldValue(n, "IsRequired").(*bool); r != nil && *r && label != "" {
+		label = t.escape(label) + " (required)"
+	} else {
+		label = t.escape(label)
+	}
+	if t.markdown && label != "" {
+		label = "**" + label + "**"
+	}
+	var value string
+	switch n := n.(type) {
+	case *InputToggle:
+		checked := n.ValueOn
+		if checked == "" {
+			checked = "true"
+		}
+		value = t.check(n.Value == checked, false) + " " + t.escape(n.Title)
+		if label == "" {
+			return value
+		}
+		return label + "\n" + value
+	case *InputChoiceSet:
+		selected := map[string]bool{}
+		for _, v := range strings.Split(n.Value, ",") {
+			selected[strings.TrimSpace(v)] = true
+		}
+		lines := make([]string, 0, len(n.Choices)+1)
+		if label != "" {
+			lines = append(lines, label)
+		}
+		for _, c := range n.Choices {
+			if c != nil {
+				lines = append(lines, t.check(selected[c.Value], !isTrue(n.IsMultiSelect))+" "+t.escape(c.Title))
+			}
+		}
+		return strings.Join(lines, "\n")
+	case *InputText:
+		value = firstNonEmpty(n.Value, n.Placeholder, "text")
+	case *InputNumber:
+		value = firstNonEmpty(n.Placeholder, "number")
+		if n.Value != 0 {
+			value = formatFloat(n.Value)
+		}
+	case *InputDate:
+		value = firstNonEmpty(n.Value, n.Placeholder, "YYYY-MM-DD")
+	case *InputTime:
+		value = firstNonEmpty(n.Value, n.Placeholder, "HH:MM")
+	}
+	value = t.escape("[" + value + "]")
+	if label == "" {
+		return value
+	}
+	return label + ": " + value
+}
+
+// check renders checkbox or radio button, in markdown it is a list item
+func (t *textWriter) check(checked, radio bool) string {
+	mark := " "
+	if checked {
+		mark = "x"
+	}
+	box := "[" + mark + "]"
+	if radio {
+		box = "(" + mark + ")"
+	}
+	if t.markdown {
+		return "- " + box
+	}
+	return box
+}
+
+// escape escapes plain text in markdown output
+func (t *textWriter) escape(s string) string {
+	if t.markdown {
+		return escapeMarkdown(s)
+	}
+	return s
+}
+
+// actions renders numbered list of actions followed by contents of Action.ShowCard
+func (t *textWriter) actions(actions []Node) []string {
+	var lines, cards []string
+	for _, a := range actions {
+		if isNilNode(a) {
+			continue
+		}
+		line, card := t.action(a)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%d. %s", len(lines)+1, line))
+		cards = append(cards, card...)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return append([]string{strings.Join(lines, "\n")}, cards...)
+}
+
+// action renders action title and contents of the card for Action.ShowCard
+func (t *textWriter) action(a Node) (string, []string) {
+	title := fieldString(a, "Title")
+	switch a := a.(type) {
+	case *ActionOpenURL:
+		return t.link(title, a.URL, a.URL), nil
+	case *ActionShowCard:
+		title = firstNonEmpty(title, a.NodeType())
+		card := append([]string{t.heading(3, t.escape(title))}, t.body(a.Card.Body, a.Card.Actions)...)
+		return t.escape(title), card
+	case *ActionSubmit, *ActionToggleVisibility:
+		return t.escape(firstNonEmpty(title, a.NodeType())), nil
+	}
+	if f := nodeFallback(a); f != nil && f.Element != nil {
+		return t.action(f.Element)
+	}
+	return "", nil
+}
@@ -0,0 +1,117 @@
+package cards
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderText(t *testing.T) {
+	for _, name := range []string{
+		"actionSet",
+		"example",
+		"inputs",
+		"media",
+	} {
+		c, err := Parse(strings.NewReader(mustReadFile("./test/" + name + ".json")))
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		got, err := RenderText(c)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		expected := mustReadFile("./test/text/" + name + ".txt")
+		if got != expected {
+			t.Errorf("%s: expected:\n%s\nbut got:\n%s", name, expected, got)
+		}
+		got, err = RenderMarkdown(c)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		expected = mustReadFile("./test/text/" + name + ".md")
+		if got != expected {
+			t.Errorf("%s: expected:\n%s\nbut got:\n%s", name, expected, got)
+		}
+	}
+}
+
+func TestRenderTextElements(t *testing.T) {
+	c := New([]Node{
+		&TextBlock{Text: "Order *#42*", Size: SizeExtraLarge},
+		&TextBlock{Text: "Hidden", IsVisible: FalsePtr()},
+		&RichTextBlock{Inlines: []*TextRun{
+			{Text: "Total: "},
+			{Text: "$10", Weight: WeightBolder},
+			{Text: " see "},
+			{Text: "details", SelectAction: &ActionOpenURL{URL: "https://example.com/42"}},
+		}},
+		&FactSet{Facts: []*Fact{{Title: "Status:", Value: "paid | shipped"}}},
+		&ImageSet{Images: []*Image{{URL: "https://example.com/1.png", AltText: "front"}}},
+		&InputText{ID: "name", Label: "Name", Placeholder: "Enter name", IsRequired: TruePtr()},
+		&InputToggle{ID: "agree", Title: "I_agree", Value: "true"},
+	}, []Node{
+		&ActionOpenURL{Title: "Track", URL: "https://example.com/track"},
+		&ActionSubmit{},
+	})
+
+	got, err := RenderText(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Order #42
+=========
+
+Total: $10 see details
+
+Status: paid | shipped
+
+- front (https://example.com/1.png)
+
+Name (required): [Enter name]
+
+[x] I_agree
+
+1. Track (https://example.com/track)
+2. Action.Submit`
+	if got != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	got, err = RenderMarkdown(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `# Order *#42*
+
+Total: **$10** see [details](https://example.com/42)
+
+| | |
+| --- | --- |
+| **Status** | paid \| shipped |
+
+- [front](https://example.com/1.png)
+
+**Name (required)**: \[Enter name\]
+
+- [x] I\_agree
+
+1. [Track](https://example.com/track)
+2. Action.Submit`
+	if got != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+}
+
+func TestMarkdownText(t *testing.T) {
+	for text, expected := range map[string]string{
+		"**bold** and _italic_":             "bold and italic",
+		"see [docs](https://example.com)":   "see docs (https://example.com)",
+		"[https://a.io](https://a.io)":      "https://a.io",
+		"- one\n- two\n\n3. three\n4. four": "- one\n- two\n\n3. three\n4. four",
+		"snake_case_name":                   "snake_case_name",
+	} {
+		if got := markdownText(text); got != expected {
+			t.Errorf("%q: expected %q but got %q", text, expected, got)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package cards
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// slot is a place in a parent (card or element) which holds child elements.
+// Exactly one of list, node and nodes is set.
+type slot struct {
+	name     string            // JSON path segment relative to the parent, e.g. "items" or "card/body"
+	list     *[]Node           // element list which can be modified in place
+	node     *Node             // single element field which can be modified in place
+	nodes    []Node            // copy of typed list (columns, images, inlines)
+	setNodes func([]Node) bool // replaces typed list, elements of other types are skipped and false is returned
+}
+
+// slots returns places holding child elements of the parent in document order.
+// Fallback element goes last.
+func slots(parent interface{}) []slot {
+	res := elementSlots(parent)
+	if f := nodeFallback(parent); f != nil && f.Element != nil {
+		res = append(res, slot{name: "fallback", node: &f.Element})
+	}
+	return res
+}
+
+func elementSlots(parent interface{}) []slot {
+	switch p := parent.(type) {
+	case *Card:
+		return []slot{
+			{name: "body", list: &p.Body},
+			{name: "actions", list: &p.Actions},
+			{name: "selectAction", node: &p.SelectAction},
+		}
+	case *NestedCard:
+		return nestedCardSlots("", p)
+	case *ActionShowCard:
+		return nestedCardSlots("card/", &p.Card)
+	case *ActionSet:
+		return []slot{{name: "actions", list: &p.Actions}}
+	case *Container:
+		return []slot{
+			{name: "items", list: &p.Items},
+			{name: "selectAction", node: &p.SelectAction},
+		}
+	case *ColumnSet:
+		columns := make([]Node, 0, len(p.Columns))
+		for _, c := range p.Columns {
+			columns = append(columns, c)
+		}
+		setColumns := func(nodes []Node) bool {
+			// a new slice, the caller's one may share the backing array
+			res := make([]*Column, 0, len(nodes))
+			for _, n := range nodes {
+				if c, ok := n.(*Column); ok {
+					res = append(res, c)
+				}
+			}
+			p.Columns = res
+			return len(res) == len(nodes)
+		}
+		return []slot{
+			{name: "columns", nodes: columns, setNodes: setColumns},
+			{name: "selectAction", node: &p.SelectAction},
+		}
+	case *Column:
+		return []slot{
+			{name: "items", list: &p.Items},
+			{name: "selectAction", node: &p.SelectAction},
+		}
+	case *ImageSet:
+		images := make([]Node, 0, len(p.Images))
+		for _, img := range p.Images {
+			images = append(images, img)
+		}
+		setImages := func(nodes []Node) bool {
+			// a new slice, the caller's one may share the backing array
+			res := make([]*Image, 0, len(nodes))
+			for _, n := range nodes {
+				if img, ok := n.(*Image); ok {
+					res = append(res, img)
+				}
+			}
+			p.Images = res
+			return len(res) == len(nodes)
+		}
+		return []slot{{name: "images", nodes: images, setNodes: setImages}}
+	case *Image:
+		return []slot{{name: "selectAction", node: &p.SelectAction}}
+	case *RichTextBlock:
+		inlines := make([]Node, 0, len(p.Inlines))
+		for _, run := range p.Inlines {
+			inlines = append(inlines, run)
+		}
+		setInlines := func(nodes []Node) bool {
+			// a new slice, the caller's one may share the backing array
+			res := make([]*TextRun, 0, len(nodes))
+			for _, n := range nodes {
+				if run, ok := n.(*TextRun); ok {
+					res = append(res, run)
+				}
+			}
+			p.Inlines = res
+			return len(res) == len(nodes)
+		}
+		return []slot{{name: "inlines", nodes: inlines, setNodes: setInlines}}
+	case *TextRun:
+		return []slot{{name: "selectAction", node: &p.SelectAction}}
+	case *InputText:
+		return []slot{{name: "inlineAction", node: &p.InlineAction}}
+	}
+	return nil
+}
+
+func nestedCardSlots(prefix string, c *NestedCard) []slot {
+	return []slot{
+		{name: prefix + "body", list: &c.Body},
+		{name: prefix + "actions", list: &c.Actions},
+		{name: prefix + "selectAction", node: &c.SelectAction},
+	}
+}
+
+// childNode is an element held by a parent with its JSON path segment relative to the parent
+type childNode struct {
+	path string
+	node Node
+}
+
+// childNodes returns elements held by the parent (card or element) in document order
+func childNodes(parent interface{}) []childNode {
+	var res []childNode
+	addAll := func(name string, nodes []Node) {
+		for i, n := range nodes {
+			if isNilNode(n) {
+				continue
+			}
+			res = append(res, childNode{path: fmt.Sprintf("%s/%d", name, i), node: n})
+		}
+	}
+	for _, s := range slots(parent) {
+		switch {
+		case s.list != nil:
+			for i, n := range *s.list {
+				// nil elements of lists are kept to be reported
+				res = append(res, childNode{path: fmt.Sprintf("%s/%d", s.name, i), node: n})
+			}
+		case s.node != nil:
+			if !isNilNode(*s.node) {
+				res = append(res, childNode{path: s.name, node: *s.node})
+			}
+		default:
+			addAll(s.name, s.nodes)
+		}
+	}
+	return res
+}
+
+// walkTree calls fn for every element under the parent depth-first.
+// Path is JSON pointer of the element, e.g. "/body/0/items/1".
+func walkTree(parent interface{}, path string, fn func(path string, n Node)) {
+	for _, child := range childNodes(parent) {
+		childPath := path + "/" + child.path
+		fn(childPath, child.node)
+		if child.node != nil {
+			walkTree(child.node, childPath, fn)
+		}
+	}
+}
+
+// isNilNode tells if n is nil or a typed nil pointer
+func isNilNode(n Node) bool {
+	if n == nil {
+		return true
+	}
+	v := reflect.ValueOf(n)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// nodeID returns value of element ID field if it has one
+func nodeID(n interface{}) string {
+	f := structField(n, "ID")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
+
+// structField returns field of struct pointed by v or invalid value
+func structField(v interface{}, name string) reflect.Value {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return rv.FieldByName(name)
+}
+
+// fieldValue returns value of the struct field or nil if there is no such field
+func fieldValue(v interface{}, name string) interface{} {
+	f := structField(v, name)
+	if !f.IsValid() || !f.CanInterface() {
+		return nil
+	}
+	return f.Interface()
+}
+
+// fieldString returns value of the string struct field or empty string
+func fieldString(v interface{}, name string) string {
+	s, _ := fieldValue(v, name).(string)
+	return s
+}
@@ -0,0 +1,130 @@
+package cards
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validation error codes
+const (
+	// CodeRequired is used when required field is missing or empty
+	CodeRequired = "required"
+	// CodeUnknownType is used when element type is not registered
+	CodeUnknownType = "unknown_type"
+	// CodeInvalid is used when custom element fails to prepare
+	CodeInvalid = "invalid"
+	// CodeInvalidValue is used when property has value which is not allowed
+	CodeInvalidValue = "invalid_value"
+)
+
+// ValidationError describes a single problem found in the card
+type ValidationError struct {
+	Path    string // JSON pointer of the element, e.g. "/body/3/items/0"
+	Type    string // element type
+	ID      string // element id if any
+	Code    string // machine-readable error code
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors holds all problems found in the card
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate walks the whole card and returns all found problems.
+// Unlike Prepare it does not stop at the first error and does not modify the card.
+func (c *Card) Validate() ValidationErrors {
+	var errs ValidationErrors
+	c.check(&reporter{errs: &errs, typ: AdaptiveCardType})
+	walkTree(c, "", func(path string, n Node) {
+		validateNode(path, n, &errs)
+	})
+	return errs
+}
+
+// validateNode runs checks of a single element
+func validateNode(path string, n Node, errs *ValidationErrors) {
+	if n == nil {
+		r := &reporter{errs: errs, path: path}
+		r.add(CodeRequired, "element is nil")
+		return
+	}
+	r := &reporter{errs: errs, path: path, typ: n.NodeType(), id: nodeID(n)}
+	if _, ok := lookupType(n.NodeType()); !ok {
+		r.add(CodeUnknownType, fmt.Sprintf("element type %q is not registered", n.NodeType()))
+		return
+	}
+	if f := nodeFallback(n); f != nil {
+		f.check(r.at("fallback"), n)
+	}
+	if c, ok := n.(checker); ok {
+		c.check(r)
+		return
+	}
+	// custom elements are validated by Prepare of their copy, the card is not modified
+	if err := CloneNode(n).Prepare(); err != nil {
+		r.add(CodeInvalid, err.Error())
+	}
+}
+
+// checker is implemented by built-in elements and their parts
+// to report problems of the element itself, without children
+type checker interface {
+	check(r *reporter)
+}
+
+// reporter collects problems of the element at path
+type reporter struct {
+	errs *ValidationErrors
+	path string
+	typ  string
+	id   string
+}
+
+func (r *reporter) add(code, msg string) {
+	*r.errs = append(*r.errs, &ValidationError{
+		Path:    r.path,
+		Type:    r.typ,
+		ID:      r.id,
+		Code:    code,
+		Message: msg,
+	})
+}
+
+func (r *reporter) required(msg string) {
+	r.add(CodeRequired, msg)
+}
+
+// at returns reporter for a part of the element, e.g. "facts/1"
+func (r *reporter) at(format string, args ...interface{}) *reporter {
+	return &reporter{
+		errs: r.errs,
+		path: r.path + "/" + fmt.Sprintf(format, args...),
+		typ:  r.typ,
+		id:   r.id,
+	}
+}
+
+// check runs checks of the element and returns the first problem as error
+func check(c checker) error {
+	var errs ValidationErrors
+	c.check(&reporter{errs: &errs})
+	if len(errs) > 0 {
+		return errors.New(errs[0].Message)
+	}
+	return nil
+}
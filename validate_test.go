@@ -0,0 +1,131 @@
+package cards
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateCollectsAllErrors(t *testing.T) {
+	c := &Card{
+		Body: []Node{
+			&TextBlock{Text: "ok"},
+			&Container{
+				Items: []Node{
+					&ColumnSet{
+						Columns: []*Column{
+							{Items: []Node{&TextBlock{Text: "ok"}}},
+							{Items: []Node{&TextBlock{ID: "empty"}}},
+						},
+					},
+					&FactSet{Facts: []*Fact{{Title: "t"}}},
+				},
+			},
+		},
+		Actions: []Node{
+			&ActionShowCard{
+				Card: NestedCard{
+					Body: []Node{&InputText{}},
+				},
+			},
+		},
+	}
+	errs := c.Validate()
+	var got [][]string
+	for _, e := range errs {
+		got = append(got, []string{e.Path, e.Type, e.ID, e.Code, e.Message})
+	}
+	expected := [][]string{
+		{"", AdaptiveCardType, "", CodeRequired, "card version is required"},
+		{"/body/1/items/0/columns/1/items/0", TextBlockType, "empty", CodeRequired, "TextBlock text is required"},
+		{"/body/1/items/1/facts/0", FactSetType, "", CodeRequired, "Fact must have value"},
+		{"/actions/0/card/body/0", InputTextType, "", CodeRequired, "InputText id is required"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%v\nbut got:\n%v", expected, got)
+	}
+	if err := c.Prepare(); err == nil || err.Error() != "card version is required" {
+		t.Errorf("expected Prepare to return first error, got %v", err)
+	}
+}
+
+func TestValidateValidCard(t *testing.T) {
+	c := New([]Node{&TextBlock{Text: "foo"}}, nil)
+	if errs := c.Validate(); len(errs) != 0 {
+		t.Errorf("expected no errors, got %s", errs)
+	}
+}
+
+func TestValidateDoesNotModifyCard(t *testing.T) {
+	badge := &testBadge{Label: "new"}
+	c := New([]Node{badge}, nil)
+	if errs := c.Validate(); len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+	if badge.Type != "" {
+		t.Errorf("expected custom element not to be prepared, got type %q", badge.Type)
+	}
+}
+
+func TestValidateEnumValues(t *testing.T) {
+	c := New([]Node{
+		&TextBlock{Text: "ok", Weight: "Bolderr", Size: "LARGE", Color: ColorGood},
+		&Container{
+			Style:     "emphasis",
+			MinHeight: "tall",
+			Items: []Node{
+				&Image{URL: "https://adaptivecards.io/content/cats/1.png", Width: "50px", Height: "20"},
+			},
+		},
+		&ColumnSet{Columns: []*Column{{Width: "2"}, {Width: "100px"}, {Width: "wide"}}},
+	}, []Node{
+		&ActionSubmit{Style: ActionStylePositive},
+		&ActionOpenURL{URL: "https://adaptivecards.io", Style: "negative"},
+	})
+	var got []string
+	for _, e := range c.Validate() {
+		if e.Code != CodeInvalidValue {
+			t.Errorf("unexpected error %s", e)
+		}
+		got = append(got, e.Error())
+	}
+	expected := []string{
+		`/body/0: weight has invalid value "Bolderr"`,
+		`/body/1: minHeight has invalid value "tall", expected pixels like "50px"`,
+		`/body/1/items/0: height has invalid value "20", expected pixels like "50px"`,
+		`/body/2/columns/2: width has invalid value "wide"`,
+		`/actions/1: style has invalid value "negative"`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%v\nbut got:\n%v", expected, got)
+	}
+}
+
+func TestPrepareRejectsEnumValues(t *testing.T) {
+	for _, n := range []Node{
+		&ColumnSet{Style: "bogus"},
+		&ActionSubmit{Style: "bogus"},
+		&ActionOpenURL{URL: "https://adaptivecards.io", Style: "bogus"},
+		&ActionShowCard{Style: "bogus"},
+	} {
+		if err := n.Prepare(); err == nil {
+			t.Errorf("%s: expected to have an error, got nil", n.NodeType())
+		}
+	}
+}
+
+func TestParsePixels(t *testing.T) {
+	for v, expected := range map[string]int{"50px": 50, " 0PX ": 0, "120 px": 120} {
+		got, err := ParsePixels(v)
+		if err != nil {
+			t.Errorf("%q: unexpected error %s", v, err)
+		}
+		if got != expected {
+			t.Errorf("%q: expected %d, got %d", v, expected, got)
+		}
+	}
+	for _, v := range []string{"", "50", "px", "-1px", "1.5px"} {
+		if _, err := ParsePixels(v); err == nil {
+			t.Errorf("%q: expected to have an error, got nil", v)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package cards
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CodeVersion is used when element or property is newer than card version
+const CodeVersion = "unsupported_version"
+
+// anyElement is a key of properties common for all elements in propertyVersions
+const anyElement = "*"
+
+// elementVersions holds versions elements were introduced in, elements missing here are 1.0
+var elementVersions = map[string]string{
+	MediaType:                  Version11,
+	RichTextBlockType:          Version12,
+	TextRunType:                Version12,
+	ActionSetType:              Version12,
+	ActionToggleVisibilityType: Version12,
+}
+
+// propertyVersions holds versions element properties were introduced in, properties missing here are 1.0
+var propertyVersions = map[string]map[string]string{
+	anyElement: {
+		"height":    Version11,
+		"isVisible": Version12,
+		"fallback":  Version12,
+		"requires":  Version12,
+	},
+	AdaptiveCardType: {
+		"selectAction":             Version11,
+		"verticalContentAlignment": Version11,
+		"minHeight":                Version12,
+		"backgroundImage":          Version12,
+	},
+	TextBlockType: {
+		"fontType": Version12,
+	},
+	ImageType: {
+		"backgroundColor": Version11,
+		"width":           Version11,
+		"selectAction":    Version11,
+	},
+	ContainerType: {
+		"selectAction":             Version11,
+		"verticalContentAlignment": Version11,
+		"bleed":                    Version12,
+		"backgroundImage":          Version12,
+		"minHeight":                Version12,
+	},
+	ColumnSetType: {
+		"selectAction": Version11,
+		"style":        Version12,
+		"bleed":        Version12,
+		"minHeight":    Version12,
+	},
+	ColumnType: {
+		"selectAction":             Version11,
+		"verticalContentAlignment": Version11,
+		"backgroundImage":          Version12,
+		"bleed":                    Version12,
+		"minHeight":                Version12,
+	},
+	ActionShowCardType: {
+		"iconUrl": Version11,
+		"style":   Version12,
+	},
+	ActionSubmitType: {
+		"iconUrl":          Version11,
+		"style":            Version12,
+		"associatedInputs": Version13,
+	},
+	ActionOpenURLType: {
+		"iconUrl": Version11,
+		"style":   Version12,
+	},
+	InputTextType: {
+		"inlineAction": Version12,
+		"regex":        Version13,
+		"label":        Version13,
+		"isRequired":   Version13,
+		"errorMessage": Version13,
+	},
+	InputNumberType: {
+		"label":        Version13,
+		"isRequired":   Version13,
+		"errorMessage": Version13,
+	},
+	InputDateType: {
+		"label":        Version13,
+		"isRequired":   Version13,
+		"errorMessage": Version13,
+	},
+	InputTimeType: {
+		"label":        Version13,
+		"isRequired":   Version13,
+		"errorMessage": Version13,
+	},
+	InputChoiceSetType: {
+		"wrap":         Version12,
+		"label":        Version13,
+		"isRequired":   Version13,
+		"errorMessage": Version13,
+	},
+	InputToggleType: {
+		"wrap":         Version12,
+		"label":        Version13,
+		"isRequired":   Version13,
+		"errorMessage": Version13,
+	},
+}
+
+// ElementVersion returns card version the element type was introduced in
+func ElementVersion(typeName string) string {
+	if v, ok := elementVersions[typeName]; ok {
+		return v
+	}
+	return Version1
+}
+
+// PropertyVersion returns card version the property (JSON name) of the element type was introduced in
+func PropertyVersion(typeName, property string) string {
+	if v, ok := propertyVersions[typeName][property]; ok {
+		return v
+	}
+	if typeName != AdaptiveCardType {
+		if v, ok := propertyVersions[anyElement][property]; ok {
+			return v
+		}
+	}
+	return Version1
+}
+
+// CompareVersions compares card versions like "1.2" and "1.10".
+// It returns -1 if a < b, 0 if a == b and 1 if a > b.
+func CompareVersions(a, b string) (int, error) {
+	am, an, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	bm, bn, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case am != bm:
+		return sign(am - bm), nil
+	case an != bn:
+		return sign(an - bn), nil
+	}
+	return 0, nil
+}
+
+func parseVersion(v string) (int, int, error) {
+	parts := strings.Split(strings.TrimSpace(v), ".")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid card version %q", v)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid card version %q", v)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid card version %q", v)
+	}
+	return major, minor, nil
+}
+
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+// isNewer tells if version v is newer than target. Invalid versions are never newer.
+func isNewer(v, target string) bool {
+	cmp, err := CompareVersions(v, target)
+	return err == nil && cmp > 0
+}
+
+// ValidateVersion reports every element and property which is newer than card version,
+// such content is ignored or dropped by clients supporting only the declared version.
+func (c *Card) ValidateVersion() ValidationErrors {
+	var errs ValidationErrors
+	r := &reporter{errs: &errs, typ: AdaptiveCardType}
+	if _, _, err := parseVersion(c.Version); err != nil {
+		r.add(CodeInvalidValue, err.Error())
+		return errs
+	}
+	checkPropertyVersions(r, AdaptiveCardType, c, c.Version)
+	walkTree(c, "", func(path string, n Node) {
+		if n == nil {
+			return
+		}
+		r := &reporter{errs: &errs, path: path, typ: n.NodeType(), id: nodeID(n)}
+		if v := ElementVersion(n.NodeType()); isNewer(v, c.Version) {
+			r.add(CodeVersion, fmt.Sprintf("%s requires version %s, card version is %s", n.NodeType(), v, c.Version))
+			return
+		}
+		checkPropertyVersions(r, n.NodeType(), n, c.Version)
+		if sc, ok := n.(*ActionShowCard); ok {
+			checkPropertyVersions(r.at("card"), AdaptiveCardType, &sc.Card, c.Version)
+		}
+	})
+	return errs
+}
+
+// checkPropertyVersions reports properties of v which are set and newer than target version
+func checkPropertyVersions(r *reporter, typeName string, v interface{}, target string) {
+	for _, prop := range setProperties(v) {
+		if pv := PropertyVersion(typeName, prop); isNewer(pv, target) {
+			r.add(CodeVersion, fmt.Sprintf("property %q of %s requires version %s, card version is %s", prop, typeName, pv, target))
+		}
+	}
+}
+
+// setProperties returns JSON names of properties which are set in v in serialization order
+func setProperties(v interface{}) []string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	var props []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return props
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return props
+		}
+		if key, ok := tok.(string); ok && key != "type" {
+			props = append(props, key)
+		}
+	}
+	return props
+}
@@ -0,0 +1,68 @@
+package cards
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateVersion(t *testing.T) {
+	c := New([]Node{
+		&TextBlock{Text: "foo", Height: HeightStretch},
+		&Media{Sources: []*MediaSource{{MimeType: "video/mp4", URL: "https://adaptivecards.io/content/cat.mp4"}}},
+		&InputText{ID: "name", Label: "Name", IsRequired: TruePtr()},
+	}, []Node{
+		&ActionToggleVisibility{Title: "Toggle", TargetElements: []TargetElement{{ElementID: "name"}}},
+		&ActionShowCard{Title: "Show", Card: NestedCard{
+			MinHeight: "100px",
+			Body:      []Node{&TextBlock{Text: "bar", IsVisible: FalsePtr()}},
+		}},
+	}).WithVersion(Version1).WithMinHeight("200px")
+
+	var got []string
+	for _, e := range c.ValidateVersion() {
+		if e.Code != CodeVersion {
+			t.Errorf("unexpected error %s", e)
+		}
+		got = append(got, e.Error())
+	}
+	expected := []string{
+		`property "minHeight" of AdaptiveCard requires version 1.2, card version is 1.0`,
+		`/body/0: property "height" of TextBlock requires version 1.1, card version is 1.0`,
+		`/body/1: Media requires version 1.1, card version is 1.0`,
+		`/body/2: property "isRequired" of Input.Text requires version 1.3, card version is 1.0`,
+		`/body/2: property "label" of Input.Text requires version 1.3, card version is 1.0`,
+		`/actions/0: Action.ToggleVisibility requires version 1.2, card version is 1.0`,
+		`/actions/1/card: property "minHeight" of AdaptiveCard requires version 1.2, card version is 1.0`,
+		`/actions/1/card/body/0: property "isVisible" of TextBlock requires version 1.2, card version is 1.0`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%v\nbut got:\n%v", expected, got)
+	}
+
+	if errs := c.WithVersion(Version13).ValidateVersion(); len(errs) != 0 {
+		t.Errorf("expected no errors for version 1.3, got %s", errs)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.2", "1.10", -1},
+		{"2.0", "1.5", 1},
+	}
+	for _, c := range cases {
+		got, err := CompareVersions(c.a, c.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.expected {
+			t.Errorf("%s vs %s: expected %d, got %d", c.a, c.b, c.expected, got)
+		}
+	}
+	if _, err := CompareVersions("1", "1.0"); err == nil {
+		t.Error("expected to have an error, got nil")
+	}
+}
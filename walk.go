@@ -0,0 +1,152 @@
+package cards
+
+import (
+	"errors"
+	"strings"
+)
+
+// Path is JSON pointer of an element relative to the root of a walk, e.g. "/body/0/items/1".
+// Empty path is the root itself.
+type Path string
+
+// Parent returns path of the element (or the root) holding the element at the path,
+// e.g. "/body/0" for "/body/0/items/1" and "/actions/0" for "/actions/0/card/body/2"
+func (p Path) Parent() Path {
+	s := string(p)
+	i := strings.LastIndexByte(s, '/')
+	if i < 0 {
+		return ""
+	}
+	last := s[i+1:]
+	s = s[:i]
+	if last != "" && strings.Trim(last, "0123456789") == "" {
+		// list index, the list name goes too
+		if j := strings.LastIndexByte(s, '/'); j >= 0 {
+			s = s[:j]
+		} else {
+			s = ""
+		}
+	}
+	// lists of Action.ShowCard are in its card
+	return Path(strings.TrimSuffix(s, "/card"))
+}
+
+// WalkFunc is called by Walk for every element, returning SkipChildren skips the element children,
+// other errors stop the walk
+type WalkFunc func(path Path, n Node) error
+
+// SkipChildren is returned by WalkFunc or Visitor.Enter to skip children of the element
+var SkipChildren = errors.New("skip children")
+
+// Visitor is called by WalkVisitor when it enters an element and when it leaves it
+// after its children are visited. Returning SkipChildren from Enter skips the children
+// (Leave is still called), other errors stop the walk.
+type Visitor interface {
+	Enter(path Path, n Node) error
+	Leave(path Path, n Node) error
+}
+
+// Walk calls fn for every element under root (*Card, *NestedCard or element) depth-first in document
+// order: body, actions, select action, inline actions, columns, images, inlines, cards of Action.ShowCard,
+// element fallbacks. Root itself isn't passed to fn.
+func Walk(root interface{}, fn WalkFunc) error {
+	return WalkVisitor(root, funcVisitor(fn))
+}
+
+type funcVisitor WalkFunc
+
+func (f funcVisitor) Enter(path Path, n Node) error { return f(path, n) }
+func (f funcVisitor) Leave(path Path, n Node) error { return nil }
+
+// WalkVisitor walks elements under root like Walk does calling v
+func WalkVisitor(root interface{}, v Visitor) error {
+	err := walkVisitor(root, "", v)
+	if err == SkipChildren {
+		return nil
+	}
+	return err
+}
+
+func walkVisitor(parent interface{}, path Path, v Visitor) error {
+	for _, child := range childNodes(parent) {
+		if isNilNode(child.node) {
+			continue
+		}
+		childPath := path + "/" + Path(child.path)
+		err := v.Enter(childPath, child.node)
+		switch err {
+		case nil:
+			if err := walkVisitor(child.node, childPath, v); err != nil {
+				return err
+			}
+		case SkipChildren:
+		default:
+			return err
+		}
+		if err := v.Leave(childPath, child.node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindByID returns the first element with the ID under root and its path, nil if there is none
+func FindByID(root interface{}, id string) (Node, Path) {
+	var res Node
+	var resPath Path
+	if id == "" {
+		return nil, ""
+	}
+	_ = Walk(root, func(path Path, n Node) error {
+		if nodeID(n) != id {
+			return nil
+		}
+		res, resPath = n, path
+		return errFound
+	})
+	return res, resPath
+}
+
+// errFound stops a walk when the element is found
+var errFound = errors.New("found")
+
+// FindAll returns elements of type T under root in document order, e.g. FindAll[*InputText](c)
+func FindAll[T Node](root interface{}) []T {
+	var res []T
+	_ = Walk(root, func(path Path, n Node) error {
+		if t, ok := n.(T); ok {
+			res = append(res, t)
+		}
+		return nil
+	})
+	return res
+}
+
+// Lookup returns the element at the path under root, nil if there is no such element
+func Lookup(root interface{}, path Path) Node {
+	var res Node
+	_ = Walk(root, func(p Path, n Node) error {
+		switch {
+		case p == path:
+			res = n
+			return errFound
+		case !strings.HasPrefix(string(path), string(p)+"/"):
+			return SkipChildren
+		}
+		return nil
+	})
+	return res
+}
+
+// Parent returns the card or element holding the element at the path: root for top level elements,
+// Action.ShowCard for elements of its card. It returns nil if there is no element at the path.
+func Parent(root interface{}, path Path) interface{} {
+	if path == "" || Lookup(root, path) == nil {
+		return nil
+	}
+	parent := path.Parent()
+	if parent == "" {
+		return root
+	}
+	return Lookup(root, parent)
+}
@@ -0,0 +1,180 @@
+package cards
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func walkTestCard() *Card {
+	return New([]Node{
+		&Container{ID: "box", Items: []Node{
+			&TextBlock{ID: "title", Text: "Title"},
+			&InputText{ID: "name", InlineAction: &ActionSubmit{ID: "send", Title: "Send"}},
+		}, SelectAction: &ActionOpenURL{ID: "open", URL: "https://example.com"}},
+		&ColumnSet{Columns: []*Column{
+			{ID: "left", Items: []Node{&Image{ID: "img", URL: "https://example.com/a.png"}}},
+			nil,
+		}},
+		&Media{ID: "video", Sources: []*MediaSource{{URL: "https://example.com/a.mp4"}},
+			Fallback: FallbackElement(&TextBlock{ID: "noVideo", Text: "No video"})},
+		nil,
+	}, []Node{
+		&ActionShowCard{ID: "more", Title: "More", Card: NestedCard{
+			Body: []Node{&InputToggle{ID: "agree", Title: "Agree"}},
+		}},
+	})
+}
+
+func TestWalk(t *testing.T) {
+	var paths []Path
+	err := Walk(walkTestCard(), func(path Path, n Node) error {
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Path{
+		"/body/0",
+		"/body/0/items/0",
+		"/body/0/items/1",
+		"/body/0/items/1/inlineAction",
+		"/body/0/selectAction",
+		"/body/1",
+		"/body/1/columns/0",
+		"/body/1/columns/0/items/0",
+		"/body/2",
+		"/body/2/fallback",
+		"/actions/0",
+		"/actions/0/card/body/0",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v but got %v", expected, paths)
+	}
+
+	paths = nil
+	_ = Walk(walkTestCard(), func(path Path, n Node) error {
+		paths = append(paths, path)
+		if _, ok := n.(*Container); ok {
+			return SkipChildren
+		}
+		return nil
+	})
+	if len(paths) != len(expected)-4 {
+		t.Errorf("expected container children to be skipped but got %v", paths)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = Walk(walkTestCard(), func(path Path, n Node) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("expected walk to stop with error but got %v after %d elements", err, count)
+	}
+}
+
+type depthVisitor struct {
+	depth, max int
+	events     []string
+}
+
+func (v *depthVisitor) Enter(path Path, n Node) error {
+	v.depth++
+	if v.depth > v.max {
+		v.max = v.depth
+	}
+	v.events = append(v.events, "enter "+n.NodeType())
+	if _, ok := n.(*ColumnSet); ok {
+		return SkipChildren
+	}
+	return nil
+}
+
+func (v *depthVisitor) Leave(path Path, n Node) error {
+	v.depth--
+	v.events = append(v.events, "leave "+n.NodeType())
+	return nil
+}
+
+func TestWalkVisitor(t *testing.T) {
+	c := New([]Node{
+		&Container{Items: []Node{&TextBlock{Text: "a"}}},
+		&ColumnSet{Columns: []*Column{{Items: []Node{}}}},
+	}, nil)
+	v := &depthVisitor{}
+	if err := WalkVisitor(c, v); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"enter Container", "enter TextBlock", "leave TextBlock", "leave Container",
+		"enter ColumnSet", "leave ColumnSet",
+	}
+	if !reflect.DeepEqual(v.events, expected) || v.depth != 0 || v.max != 2 {
+		t.Errorf("unexpected visit %v, depth %d, max %d", v.events, v.depth, v.max)
+	}
+}
+
+func TestFind(t *testing.T) {
+	c := walkTestCard()
+	for id, path := range map[string]Path{
+		"title":   "/body/0/items/0",
+		"send":    "/body/0/items/1/inlineAction",
+		"img":     "/body/1/columns/0/items/0",
+		"noVideo": "/body/2/fallback",
+		"agree":   "/actions/0/card/body/0",
+		"missing": "",
+		"":        "",
+	} {
+		n, p := FindByID(c, id)
+		if p != path || (path == "") != (n == nil) {
+			t.Errorf("expected %s at %q but got %q", id, path, p)
+		}
+		if path != "" && Lookup(c, path) != n {
+			t.Errorf("expected lookup of %q to return %s", path, id)
+		}
+	}
+
+	texts := FindAll[*TextBlock](c)
+	if len(texts) != 2 || texts[0].ID != "title" || texts[1].ID != "noVideo" {
+		t.Errorf("unexpected text blocks %v", texts)
+	}
+	if inputs := FindAll[*InputToggle](&c.Actions[0].(*ActionShowCard).Card); len(inputs) != 1 {
+		t.Errorf("expected toggle in nested card but got %v", inputs)
+	}
+	if actions := FindAll[*ActionSubmit](c.Body[0]); len(actions) != 1 || actions[0].ID != "send" {
+		t.Errorf("unexpected actions %v", actions)
+	}
+}
+
+func TestParent(t *testing.T) {
+	c := walkTestCard()
+	for path, expected := range map[Path]interface{}{
+		"/body/0":                      c,
+		"/body/0/items/1":              c.Body[0],
+		"/body/0/items/1/inlineAction": c.Body[0].(*Container).Items[1],
+		"/body/0/selectAction":         c.Body[0],
+		"/body/1/columns/0/items/0":    c.Body[1].(*ColumnSet).Columns[0],
+		"/body/2/fallback":             c.Body[2],
+		"/actions/0/card/body/0":       c.Actions[0],
+		"/body/9":                      nil,
+		"":                             nil,
+	} {
+		if got := Parent(c, path); got != expected {
+			t.Errorf("expected parent of %q to be %v but got %v", path, expected, got)
+		}
+	}
+	for path, expected := range map[Path]Path{
+		"/body/0/items/12":       "/body/0",
+		"/actions/3/card/body/0": "/actions/3",
+		"/body/0/fallback":       "/body/0",
+		"/body/0":                "",
+		"":                       "",
+	} {
+		if got := path.Parent(); got != expected {
+			t.Errorf("expected parent path of %q to be %q but got %q", path, expected, got)
+		}
+	}
+}